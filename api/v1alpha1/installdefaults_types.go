@@ -0,0 +1,87 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InstallDefaultsName is the name of the InstallDefaults singleton, the only name the API accepts.
+const InstallDefaultsName = "cluster"
+
+// InstallDefaultsSpec defines the install configuration every Operator defaults to.
+//
+// The settings of an Operator take precedence over the defaults, which take precedence over the
+// flags of the operator-controller:
+//   - environment variables and volumes of the Operator config replace the default ones with the
+//     same name, and volume mounts the ones with the same mount path. They are added to the others;
+//   - the node selector of the Operator config replaces the default one, as it replaces the node
+//     selector of the pods;
+//   - resource requests and limits of the Operator config replace the default ones for the same
+//     resource, and are added to the others;
+//   - tolerations and environment variable sources of the Operator config are added to the default ones;
+//   - the pull secret of the Operator replaces the default one;
+//   - the progress deadline of the Operator replaces the default one, which replaces the one set
+//     by the --progress-deadline flag.
+//
+// The resulting configuration is reported in the status of each Operator.
+type InstallDefaultsSpec struct {
+	//+kubebuilder:Optional
+	// Config is the default configuration of the Deployments of the bundles. It is only applied to a
+	// bundle whose Deployments it changes, as operator-controller then renders the objects of the
+	// bundle instead of installing the bundle image, and only if the catalog provides them.
+	Config *DeploymentConfig `json:"config,omitempty"`
+
+	//+kubebuilder:validation:Minimum:=0
+	//+kubebuilder:Optional
+	// ProgressDeadlineSeconds is the default maximum time in seconds for the resolved bundle of an
+	// Operator to be installed. A value of 0 disables the deadline.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	//+kubebuilder:validation:MaxLength:=253
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	//+kubebuilder:Optional
+	// PullSecret is the name of the default Secret holding the credentials to pull bundle images,
+	// in the namespace of the provisioner that unpacks the bundles.
+	PullSecret string `json:"pullSecret,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the install defaults must be named cluster"
+
+// InstallDefaults is the Schema for the installdefaults API. It is a singleton named "cluster"
+// that supplies the default install configuration of every Operator.
+type InstallDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec InstallDefaultsSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// InstallDefaultsList contains a list of InstallDefaults
+type InstallDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InstallDefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InstallDefaults{}, &InstallDefaultsList{})
+}
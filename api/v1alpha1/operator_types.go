@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/operator-framework/operator-controller/internal/conditionsets"
@@ -42,28 +44,322 @@ type OperatorSpec struct {
 	//+kubebuilder:validation:Pattern:=^[a-z0-9]+([\.-][a-z0-9]+)*$
 	// Channel constraint defintion
 	Channel string `json:"channel,omitempty"`
+
+	//+kubebuilder:validation:MaxLength:=64
+	//+kubebuilder:validation:Pattern=^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-(0|[1-9]\d*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*)(\.(0|[1-9]\d*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*))*)?(\+([0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*))?$
+	//+kubebuilder:Optional
+	// ForceUpgradeVersion allows the Operator to be upgraded to the bundle of the given version even
+	// when the installed bundle reports that it is not ready to be upgraded. Upgrades to any other
+	// version still wait for the installed bundle to be ready.
+	ForceUpgradeVersion string `json:"forceUpgradeVersion,omitempty"`
+
+	//+kubebuilder:validation:Minimum:=0
+	//+kubebuilder:Optional
+	// ProgressDeadlineSeconds is the maximum time in seconds for the resolved bundle to be installed
+	// before the installation is considered to have failed. If not specified, the progress deadline
+	// of the InstallDefaults applies, or the default progress deadline of the operator-controller.
+	// A value of 0 disables the deadline.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	//+kubebuilder:Optional
+	// RollbackOnFailure returns the Operator to the last bundle that was successfully installed and
+	// found healthy when a newer bundle fails: when the newer bundle is invalid, or is not installed or
+	// not healthy within the progress deadline. Failures are not rolled back from without a progress
+	// deadline, invalid bundles aside. Resolution then stays on the last known-good bundle until the
+	// spec changes, or until the catalog provides another bundle of the same or a higher version than
+	// the failed one.
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+
+	//+kubebuilder:Optional
+	//+listType=map
+	//+listMapKey=name
+	// HealthChecks are checks that must pass, in addition to the checks of the installed workloads
+	// and the health checks declared by the bundle, for the Operator to be considered healthy.
+	HealthChecks []HealthCheck `json:"healthChecks,omitempty"`
+
+	//+kubebuilder:validation:MaxLength:=63
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	//+kubebuilder:Optional
+	// InstallNamespace is the namespace the namespaced resources of the bundle are installed in.
+	// If not specified, the namespace suggested by the bundle is used, or <packageName>-system if
+	// the bundle suggests none. The namespace is created if the bundle does not create it.
+	// Installing the bundle in another namespace than its default one requires the catalog to provide
+	// the objects of the bundle, which operator-controller renders for the namespace.
+	InstallNamespace string `json:"installNamespace,omitempty"`
+
+	//+kubebuilder:Optional
+	// DeleteInstallNamespace deletes the install namespace when the Operator is deleted, provided
+	// that the namespace was created for the Operator by operator-controller.
+	DeleteInstallNamespace bool `json:"deleteInstallNamespace,omitempty"`
+
+	//+kubebuilder:Optional
+	//+listType=set
+	// WatchNamespaces are the namespaces the operator watches. If not specified, the operator watches
+	// all namespaces, which requires the bundle to support the AllNamespaces install mode. Watching
+	// the install namespace requires the OwnNamespace or SingleNamespace install mode, watching another
+	// namespace requires the SingleNamespace install mode, and watching several namespaces requires
+	// the MultiNamespace install mode. Watching specific namespaces requires the catalog to provide
+	// the objects of the bundle, which operator-controller renders for the namespaces.
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+
+	//+kubebuilder:Optional
+	// PreflightServiceAccount is a service account whose permissions the preflight checks verify
+	// before the bundle is installed: the bundle is only installed if the service account is allowed
+	// to manage the objects of the bundle, as rendered for the Operator, and to grant the permissions
+	// the bundle grants to its operator. It is a check only, not a least-privilege install: the
+	// objects are still applied with the permissions of the provisioner, which cannot act as a
+	// service account. The permissions of the service account can only be checked when the catalog
+	// provides the objects of the bundle, and the bundle is not installed otherwise.
+	PreflightServiceAccount *ServiceAccountReference `json:"preflightServiceAccount,omitempty"`
+
+	//+kubebuilder:validation:MaxLength:=253
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	//+kubebuilder:Optional
+	// PullSecret is the name of the Secret holding the credentials to pull the bundle image from an
+	// authenticated registry. The Secret must be of type kubernetes.io/dockerconfigjson and live in the
+	// namespace of the provisioner that unpacks the bundle. If not specified, the pull secret of the
+	// InstallDefaults is used. It is not needed when operator-controller renders the objects of the
+	// bundle from the catalog, as the bundle image is then not pulled.
+	PullSecret string `json:"pullSecret,omitempty"`
+
+	//+kubebuilder:Optional
+	// Config customizes the Deployments of the bundle when it is installed, e.g. to set proxy
+	// environment variables or resource limits, without modifying the bundle. It is merged over the
+	// config of the InstallDefaults. Configuring the Deployments requires the catalog to provide the
+	// objects of the bundle, which operator-controller renders with the config applied.
+	Config *DeploymentConfig `json:"config,omitempty"`
+
+	//+kubebuilder:Optional
+	// Patches are applied to the objects of the bundle when it is installed, after the config, for
+	// changes that the config cannot express, e.g. changing the failure policy of a webhook. Patches
+	// that match no object of the bundle are reported by the PatchesApplied condition, and the bundle
+	// is not installed if a patch fails to apply. Patching the objects requires the catalog to provide
+	// the objects of the bundle, which operator-controller renders with the patches applied.
+	Patches []ManifestPatch `json:"patches,omitempty"`
+}
+
+// PatchType is the type of a patch.
+// +kubebuilder:validation:Enum=StrategicMerge;JSON6902
+type PatchType string
+
+const (
+	// PatchTypeStrategicMerge patches are merged into the objects they target, following the
+	// patch strategies of the built-in kinds. Other kinds are merged as JSON merge patches.
+	PatchTypeStrategicMerge PatchType = "StrategicMerge"
+	// PatchTypeJSON6902 patches are lists of JSON patch operations, as defined by RFC 6902.
+	PatchTypeJSON6902 PatchType = "JSON6902"
+)
+
+// ManifestPatch is a patch of the objects of a bundle.
+type ManifestPatch struct {
+	// Target selects the objects the patch applies to.
+	Target PatchTarget `json:"target"`
+
+	//+kubebuilder:default:=StrategicMerge
+	//+kubebuilder:Optional
+	// Type is the type of the patch.
+	Type PatchType `json:"type,omitempty"`
+
+	//+kubebuilder:validation:MinLength:=1
+	// Patch is the patch, in YAML or JSON.
+	Patch string `json:"patch"`
+}
+
+// PatchTarget selects objects of a bundle.
+type PatchTarget struct {
+	//+kubebuilder:Optional
+	// Group is the API group of the objects, empty for the core group.
+	Group string `json:"group,omitempty"`
+
+	//+kubebuilder:Optional
+	// Version is the API version of the objects. If not specified, objects of any version match.
+	Version string `json:"version,omitempty"`
+
+	//+kubebuilder:validation:MinLength:=1
+	// Kind is the kind of the objects.
+	Kind string `json:"kind"`
+
+	//+kubebuilder:Optional
+	// Name is the name of the object. If not specified, every object of the kind matches.
+	Name string `json:"name,omitempty"`
+
+	//+kubebuilder:Optional
+	// Namespace is the namespace of the objects. If not specified, objects in any namespace match.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// DeploymentConfig customizes the Deployments of a bundle. It applies to every Deployment of the
+// bundle, and to every container of their pods, init containers excepted.
+type DeploymentConfig struct {
+	//+kubebuilder:Optional
+	//+listType=map
+	//+listMapKey=name
+	// Env are environment variables set in the containers, in addition to those of the bundle.
+	// A variable the bundle also sets is overridden.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	//+kubebuilder:Optional
+	// EnvFrom are sources of environment variables added to the containers, unless they already
+	// have them.
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	//+kubebuilder:Optional
+	// Resources replace the compute resource requirements of the containers. The requests and
+	// limits of an Operator config are merged per resource over those of the InstallDefaults config.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	//+kubebuilder:Optional
+	// Tolerations are added to the tolerations of the pods, unless they already have them.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	//+kubebuilder:Optional
+	// NodeSelector replaces the node selector of the pods. The node selector of an Operator config
+	// also replaces the one of the InstallDefaults config as a whole.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	//+kubebuilder:Optional
+	//+listType=map
+	//+listMapKey=name
+	// Volumes are added to the volumes of the pods. A volume the bundle also defines is overridden.
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	//+kubebuilder:Optional
+	// VolumeMounts are added to the volume mounts of the containers. A volume mount the bundle also
+	// defines at the same mount path is overridden.
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+}
+
+// ServiceAccountReference identifies a service account.
+type ServiceAccountReference struct {
+	//+kubebuilder:validation:MaxLength:=253
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	// Name is the name of the service account.
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:MaxLength:=63
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	//+kubebuilder:Optional
+	// Namespace is the namespace of the service account. If not specified, the service account is
+	// looked up in the install namespace.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// HealthCheck is a custom check of the health of an installed Operator.
+// Exactly one of condition, expression and httpGet must be specified.
+type HealthCheck struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name identifies the health check.
+	Name string `json:"name"`
+
+	//+kubebuilder:Optional
+	// Object is the object inspected by condition and expression checks. operator-controller must be
+	// allowed to get it, through a ClusterRole labeled
+	// operators.operatorframework.io/aggregate-to-health-check=true.
+	Object *HealthCheckObject `json:"object,omitempty"`
+
+	//+kubebuilder:Optional
+	// Condition checks that a condition of the object has the expected status.
+	Condition *ConditionHealthCheck `json:"condition,omitempty"`
+
+	//+kubebuilder:Optional
+	// Expression is a CEL expression evaluated against the object, available as "self",
+	// which must evaluate to true. Example: self.status.phase == "Ready"
+	Expression string `json:"expression,omitempty"`
+
+	//+kubebuilder:Optional
+	// HTTPGet checks that a GET request to an endpoint succeeds. Only health checks declared by the
+	// Operator may specify it, not the ones declared by its bundle.
+	HTTPGet *HTTPGetHealthCheck `json:"httpGet,omitempty"`
+}
+
+// HealthCheckObject identifies the object inspected by a health check.
+type HealthCheckObject struct {
+	// APIVersion is the API version of the object.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	//+kubebuilder:Optional
+	// Namespace is the namespace of the object, if it is namespaced.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ConditionHealthCheck checks a condition found in the status of an object.
+type ConditionHealthCheck struct {
+	// Type is the type of the condition.
+	Type string `json:"type"`
+	//+kubebuilder:validation:Enum:=True;False;Unknown
+	//+kubebuilder:default:=True
+	//+kubebuilder:Optional
+	// Status is the expected status of the condition.
+	Status metav1.ConditionStatus `json:"status,omitempty"`
+}
+
+// HTTPGetHealthCheck checks an HTTP endpoint, which succeeds if it responds with a status
+// code greater than or equal to 200 and less than 400.
+type HTTPGetHealthCheck struct {
+	//+kubebuilder:validation:Pattern:=`^https?://`
+	// URL is the endpoint to check, e.g. http://my-operator.my-namespace.svc:8081/healthz
+	URL string `json:"url"`
 }
 
 const (
 	// TODO(user): add more Types, here and into init()
-	TypeInstalled = "Installed"
-	TypeResolved  = "Resolved"
-
-	ReasonBundleLookupFailed        = "BundleLookupFailed"
-	ReasonInstallationFailed        = "InstallationFailed"
-	ReasonInstallationStatusUnknown = "InstallationStatusUnknown"
-	ReasonInstallationSucceeded     = "InstallationSucceeded"
-	ReasonInvalidSpec               = "InvalidSpec"
-	ReasonResolutionFailed          = "ResolutionFailed"
-	ReasonResolutionUnknown         = "ResolutionUnknown"
-	ReasonSuccess                   = "Success"
+	TypeHealthy          = "Healthy"
+	TypeInstalled        = "Installed"
+	TypePatchesApplied   = "PatchesApplied"
+	TypePreflightPassed  = "PreflightPassed"
+	TypeProgressing      = "Progressing"
+	TypeResolved         = "Resolved"
+	TypeUpgradeAvailable = "UpgradeAvailable"
+	TypeUpgradeBlocked   = "UpgradeBlocked"
+
+	ReasonApplying                   = "Applying"
+	ReasonBundleDeploymentStale      = "BundleDeploymentStale"
+	ReasonBundleLookupFailed         = "BundleLookupFailed"
+	ReasonHealthStatusUnknown        = "HealthStatusUnknown"
+	ReasonHealthy                    = "Healthy"
+	ReasonImagePullUnauthorized      = "ImagePullUnauthorized"
+	ReasonInstallationFailed         = "InstallationFailed"
+	ReasonInstallationStatusUnknown  = "InstallationStatusUnknown"
+	ReasonInstallationSucceeded      = "InstallationSucceeded"
+	ReasonInvalidBundle              = "InvalidBundle"
+	ReasonInvalidHealthChecks        = "InvalidHealthChecks"
+	ReasonInvalidSpec                = "InvalidSpec"
+	ReasonNewerVersionsAvailable     = "NewerVersionsAvailable"
+	ReasonNotUpgradeable             = "NotUpgradeable"
+	ReasonPatchFailed                = "PatchFailed"
+	ReasonPatchStatusUnknown         = "PatchStatusUnknown"
+	ReasonPatchesApplied             = "PatchesApplied"
+	ReasonPatchesNotMatched          = "PatchesNotMatched"
+	ReasonPreflightChecksFailed      = "PreflightChecksFailed"
+	ReasonPreflightChecksPassed      = "PreflightChecksPassed"
+	ReasonPreflightStatusUnknown     = "PreflightStatusUnknown"
+	ReasonProgressDeadlineExceeded   = "ProgressDeadlineExceeded"
+	ReasonResolutionFailed           = "ResolutionFailed"
+	ReasonResolutionUnknown          = "ResolutionUnknown"
+	ReasonSuccess                    = "Success"
+	ReasonUnhealthy                  = "Unhealthy"
+	ReasonUnpacking                  = "Unpacking"
+	ReasonUpToDate                   = "UpToDate"
+	ReasonUpgradeAllowed             = "UpgradeAllowed"
+	ReasonUpgradeAvailabilityUnknown = "UpgradeAvailabilityUnknown"
+	ReasonUpgradeForced              = "UpgradeForced"
+	ReasonUpgradeStatusUnknown       = "UpgradeStatusUnknown"
 )
 
 func init() {
 	// TODO(user): add Types from above
 	conditionsets.ConditionTypes = append(conditionsets.ConditionTypes,
 		TypeInstalled,
+		TypeProgressing,
+		TypeHealthy,
+		TypePreflightPassed,
+		TypePatchesApplied,
 		TypeResolved,
+		TypeUpgradeAvailable,
+		TypeUpgradeBlocked,
 	)
 	// TODO(user): add Reasons from above
 	conditionsets.ConditionReasons = append(conditionsets.ConditionReasons,
@@ -75,15 +371,95 @@ func init() {
 		ReasonInstallationStatusUnknown,
 		ReasonInvalidSpec,
 		ReasonSuccess,
+		ReasonNotUpgradeable,
+		ReasonUpgradeAllowed,
+		ReasonUpgradeForced,
+		ReasonUpgradeStatusUnknown,
+		ReasonNewerVersionsAvailable,
+		ReasonUpToDate,
+		ReasonUpgradeAvailabilityUnknown,
+		ReasonApplying,
+		ReasonBundleDeploymentStale,
+		ReasonInvalidBundle,
+		ReasonUnpacking,
+		ReasonProgressDeadlineExceeded,
+		ReasonHealthy,
+		ReasonUnhealthy,
+		ReasonHealthStatusUnknown,
+		ReasonInvalidHealthChecks,
+		ReasonPreflightChecksPassed,
+		ReasonPreflightChecksFailed,
+		ReasonPreflightStatusUnknown,
+		ReasonPatchesApplied,
+		ReasonPatchesNotMatched,
+		ReasonPatchFailed,
+		ReasonPatchStatusUnknown,
+		ReasonImagePullUnauthorized,
 	)
 }
 
 // OperatorStatus defines the observed state of Operator
 type OperatorStatus struct {
+	// ObservedGeneration is the most recent generation of the Operator spec that has been reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// +optional
 	InstalledBundleResource string `json:"installedBundleResource,omitempty"`
+	// InstallNamespace is the namespace the namespaced resources of the resolved bundle are
+	// installed in.
+	// +optional
+	InstallNamespace string `json:"installNamespace,omitempty"`
+	// EffectiveConfig is the configuration applied to the Deployments of the bundle: the config of the
+	// Operator merged over the config of the InstallDefaults. It is not applied if the bundle is not
+	// installed. The config of the InstallDefaults alone is not reported when it cannot be applied, as
+	// the catalog does not provide the objects of the bundle.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	EffectiveConfig *DeploymentConfig `json:"effectiveConfig,omitempty"`
+	// EffectivePullSecret is the name of the Secret the bundle image is pulled with: the pull secret
+	// of the Operator, or the one of the InstallDefaults. It is not set when operator-controller
+	// renders the bundle, as the rendered objects are installed without pulling the bundle image.
+	// +optional
+	EffectivePullSecret string `json:"effectivePullSecret,omitempty"`
+	// EffectiveProgressDeadlineSeconds is the maximum time in seconds for the resolved bundle to be
+	// installed: the progress deadline of the Operator, the one of the InstallDefaults, or the
+	// default progress deadline of the operator-controller. A value of 0 disables the deadline.
+	// +optional
+	EffectiveProgressDeadlineSeconds *int32 `json:"effectiveProgressDeadlineSeconds,omitempty"`
 	// +optional
 	ResolvedBundleResource string `json:"resolvedBundleResource,omitempty"`
+	// ResolvedBundle describes the bundle referenced by ResolvedBundleResource.
+	// +optional
+	ResolvedBundle *ResolvedBundle `json:"resolvedBundle,omitempty"`
+	// PermissionPreview describes the permissions the resolved bundle grants to its operator, and how
+	// they differ from those granted by the installed bundle. It is set before the resolved bundle is
+	// installed, so that the permissions can be reviewed ahead of an install or upgrade.
+	// +optional
+	PermissionPreview *PermissionPreview `json:"permissionPreview,omitempty"`
+	// AvailableUpgrades lists, for each channel of the package, the versions that are newer than the
+	// resolved bundle, whether or not they satisfy the constraints of the Operator. Only the 5 latest
+	// versions of each channel are listed, from the highest down. The versions available in the
+	// channel of the resolved bundle are listed first, followed by the other channels.
+	// +optional
+	AvailableUpgrades []AvailableUpgrade `json:"availableUpgrades,omitempty"`
+
+	// LastKnownGoodBundle is the last bundle that was successfully installed, and not found unhealthy.
+	// +optional
+	LastKnownGoodBundle *BundleMetadata `json:"lastKnownGoodBundle,omitempty"`
+	// FailedBundle is the bundle that failed to install, and was rolled back from.
+	// +optional
+	FailedBundle *FailedBundle `json:"failedBundle,omitempty"`
+
+	// InstallAttempt records the progress of the most recent attempt to install the resolved bundle.
+	// +optional
+	InstallAttempt *InstallAttempt `json:"installAttempt,omitempty"`
+
+	// InstallHistory lists the bundles the Operator installed or failed to install, oldest first.
+	// Only the most recent revisions are kept.
+	// +optional
+	InstallHistory []InstallRevision `json:"installHistory,omitempty"`
 
 	// +patchMergeKey=type
 	// +patchStrategy=merge
@@ -92,6 +468,143 @@ type OperatorStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
+// InstallAttempt describes an attempt to install a bundle.
+type InstallAttempt struct {
+	// BundleResource is the bundle the attempt installs.
+	BundleResource string `json:"bundleResource"`
+	// Generation is the generation of the Operator spec the attempt installs the bundle with.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+	// StartTime is the time the attempt started.
+	StartTime metav1.Time `json:"startTime"`
+	// CompletionTime is the time the bundle was first reported as installed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// FailureTime is the time the attempt was given up on, as the progress deadline was exceeded.
+	// +optional
+	FailureTime *metav1.Time `json:"failureTime,omitempty"`
+}
+
+// BundleMetadata identifies a bundle and where it comes from.
+type BundleMetadata struct {
+	// Name is the name of the bundle.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Package is the name of the package the bundle belongs to.
+	Package string `json:"package"`
+	// Version is the semver version of the bundle.
+	Version string `json:"version"`
+	// Channel is the channel the bundle was resolved from.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+	// Catalog is the name of the catalog providing the bundle.
+	// +optional
+	Catalog string `json:"catalog,omitempty"`
+	// Image is the reference to the bundle image.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// ResolvedBundle describes the bundle resolved for an Operator.
+type ResolvedBundle struct {
+	BundleMetadata `json:",inline"`
+	// Dependencies lists the bundles selected by resolution to satisfy the dependencies of the
+	// resolved bundle, directly or transitively.
+	// +optional
+	Dependencies []BundleMetadata `json:"dependencies,omitempty"`
+	// ClusterProvidedAPIs lists the APIs required by the resolved bundle or its dependencies that
+	// the cluster already serves, so that no bundle needed to be resolved to provide them.
+	// +optional
+	ClusterProvidedAPIs []GroupVersionKind `json:"clusterProvidedAPIs,omitempty"`
+}
+
+// GroupVersionKind identifies a kind of API object.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// PermissionPreview describes the RBAC permissions a bundle grants to its operator.
+type PermissionPreview struct {
+	// BundleResource is the bundle granting the permissions.
+	BundleResource string `json:"bundleResource"`
+	// Summary summarizes the permissions granted by the bundle, and how they change from the
+	// installed bundle.
+	Summary string `json:"summary"`
+	// ClusterRules are the rules granted cluster-wide. The namespaced permissions of an operator
+	// watching all namespaces are granted cluster-wide.
+	// +optional
+	ClusterRules []rbacv1.PolicyRule `json:"clusterRules,omitempty"`
+	// NamespaceRules are the rules granted in specific namespaces.
+	// +optional
+	NamespaceRules []NamespacePolicyRules `json:"namespaceRules,omitempty"`
+	// InstalledBundleResource is the installed bundle the permissions are compared to. It is empty
+	// if no other bundle is installed.
+	// +optional
+	InstalledBundleResource string `json:"installedBundleResource,omitempty"`
+	// AddedPermissions lists the permissions granted by the bundle that the installed bundle does
+	// not grant, e.g. "create deployments.apps in namespace foo".
+	// +optional
+	AddedPermissions []string `json:"addedPermissions,omitempty"`
+	// RemovedPermissions lists the permissions granted by the installed bundle that the bundle no
+	// longer grants.
+	// +optional
+	RemovedPermissions []string `json:"removedPermissions,omitempty"`
+}
+
+// NamespacePolicyRules are the rules granted in a namespace.
+type NamespacePolicyRules struct {
+	// Namespace is the namespace the rules are granted in.
+	Namespace string `json:"namespace"`
+	// Rules are the rules granted in the namespace.
+	Rules []rbacv1.PolicyRule `json:"rules"`
+}
+
+// FailedBundle describes a bundle that failed to install.
+type FailedBundle struct {
+	BundleMetadata `json:",inline"`
+	// Generation is the generation of the Operator spec the bundle failed to install with.
+	Generation int64 `json:"generation"`
+	// FailureTime is the time the bundle was found to have failed to install.
+	FailureTime metav1.Time `json:"failureTime"`
+	// Message describes the failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// InstallOutcome is the outcome of the installation of a bundle.
+// +kubebuilder:validation:Enum=Succeeded;Failed
+type InstallOutcome string
+
+const (
+	InstallOutcomeSucceeded InstallOutcome = "Succeeded"
+	InstallOutcomeFailed    InstallOutcome = "Failed"
+)
+
+// InstallRevision describes a bundle the Operator installed, or attempted to install.
+type InstallRevision struct {
+	BundleMetadata `json:",inline"`
+	// InstallTime is the time the bundle was found to be installed, or to have failed to install.
+	InstallTime metav1.Time `json:"installTime"`
+	// ReplaceTime is the time the installed bundle was replaced by another bundle.
+	// +optional
+	ReplaceTime *metav1.Time `json:"replaceTime,omitempty"`
+	// Outcome is the outcome of the installation.
+	Outcome InstallOutcome `json:"outcome"`
+	// Message describes the outcome of a failed installation.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// AvailableUpgrade is a version of the package that is newer than the resolved bundle.
+type AvailableUpgrade struct {
+	// Version is the version of the newer bundle.
+	Version string `json:"version"`
+	// Channel is the channel in which the newer bundle is available.
+	Channel string `json:"channel"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:scope=Cluster
 //+kubebuilder:subresource:status
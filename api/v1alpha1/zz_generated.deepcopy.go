@@ -22,16 +22,383 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailableUpgrade) DeepCopyInto(out *AvailableUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailableUpgrade.
+func (in *AvailableUpgrade) DeepCopy() *AvailableUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailableUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleMetadata) DeepCopyInto(out *BundleMetadata) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleMetadata.
+func (in *BundleMetadata) DeepCopy() *BundleMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionHealthCheck) DeepCopyInto(out *ConditionHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionHealthCheck.
+func (in *ConditionHealthCheck) DeepCopy() *ConditionHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfig) DeepCopyInto(out *DeploymentConfig) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfig.
+func (in *DeploymentConfig) DeepCopy() *DeploymentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedBundle) DeepCopyInto(out *FailedBundle) {
+	*out = *in
+	out.BundleMetadata = in.BundleMetadata
+	in.FailureTime.DeepCopyInto(&out.FailureTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedBundle.
+func (in *FailedBundle) DeepCopy() *FailedBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupVersionKind) DeepCopyInto(out *GroupVersionKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupVersionKind.
+func (in *GroupVersionKind) DeepCopy() *GroupVersionKind {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupVersionKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGetHealthCheck) DeepCopyInto(out *HTTPGetHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGetHealthCheck.
+func (in *HTTPGetHealthCheck) DeepCopy() *HTTPGetHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGetHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	if in.Object != nil {
+		in, out := &in.Object, &out.Object
+		*out = new(HealthCheckObject)
+		**out = **in
+	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(ConditionHealthCheck)
+		**out = **in
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(HTTPGetHealthCheck)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckObject) DeepCopyInto(out *HealthCheckObject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckObject.
+func (in *HealthCheckObject) DeepCopy() *HealthCheckObject {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallAttempt) DeepCopyInto(out *InstallAttempt) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailureTime != nil {
+		in, out := &in.FailureTime, &out.FailureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallAttempt.
+func (in *InstallAttempt) DeepCopy() *InstallAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallDefaults) DeepCopyInto(out *InstallDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallDefaults.
+func (in *InstallDefaults) DeepCopy() *InstallDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InstallDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallDefaultsList) DeepCopyInto(out *InstallDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InstallDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallDefaultsList.
+func (in *InstallDefaultsList) DeepCopy() *InstallDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InstallDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallDefaultsSpec) DeepCopyInto(out *InstallDefaultsSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(DeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallDefaultsSpec.
+func (in *InstallDefaultsSpec) DeepCopy() *InstallDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallRevision) DeepCopyInto(out *InstallRevision) {
+	*out = *in
+	out.BundleMetadata = in.BundleMetadata
+	in.InstallTime.DeepCopyInto(&out.InstallTime)
+	if in.ReplaceTime != nil {
+		in, out := &in.ReplaceTime, &out.ReplaceTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallRevision.
+func (in *InstallRevision) DeepCopy() *InstallRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestPatch) DeepCopyInto(out *ManifestPatch) {
+	*out = *in
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestPatch.
+func (in *ManifestPatch) DeepCopy() *ManifestPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePolicyRules) DeepCopyInto(out *NamespacePolicyRules) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePolicyRules.
+func (in *NamespacePolicyRules) DeepCopy() *NamespacePolicyRules {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePolicyRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Operator) DeepCopyInto(out *Operator) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -88,6 +455,38 @@ func (in *OperatorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorSpec) DeepCopyInto(out *OperatorSpec) {
 	*out = *in
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = make([]HealthCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WatchNamespaces != nil {
+		in, out := &in.WatchNamespaces, &out.WatchNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreflightServiceAccount != nil {
+		in, out := &in.PreflightServiceAccount, &out.PreflightServiceAccount
+		*out = new(ServiceAccountReference)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(DeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ManifestPatch, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSpec.
@@ -103,9 +502,56 @@ func (in *OperatorSpec) DeepCopy() *OperatorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
 	*out = *in
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(DeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveProgressDeadlineSeconds != nil {
+		in, out := &in.EffectiveProgressDeadlineSeconds, &out.EffectiveProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResolvedBundle != nil {
+		in, out := &in.ResolvedBundle, &out.ResolvedBundle
+		*out = new(ResolvedBundle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PermissionPreview != nil {
+		in, out := &in.PermissionPreview, &out.PermissionPreview
+		*out = new(PermissionPreview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]AvailableUpgrade, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastKnownGoodBundle != nil {
+		in, out := &in.LastKnownGoodBundle, &out.LastKnownGoodBundle
+		*out = new(BundleMetadata)
+		**out = **in
+	}
+	if in.FailedBundle != nil {
+		in, out := &in.FailedBundle, &out.FailedBundle
+		*out = new(FailedBundle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallAttempt != nil {
+		in, out := &in.InstallAttempt, &out.InstallAttempt
+		*out = new(InstallAttempt)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallHistory != nil {
+		in, out := &in.InstallHistory, &out.InstallHistory
+		*out = make([]InstallRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -121,3 +567,98 @@ func (in *OperatorStatus) DeepCopy() *OperatorStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchTarget) DeepCopyInto(out *PatchTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchTarget.
+func (in *PatchTarget) DeepCopy() *PatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PermissionPreview) DeepCopyInto(out *PermissionPreview) {
+	*out = *in
+	if in.ClusterRules != nil {
+		in, out := &in.ClusterRules, &out.ClusterRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamespaceRules != nil {
+		in, out := &in.NamespaceRules, &out.NamespaceRules
+		*out = make([]NamespacePolicyRules, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AddedPermissions != nil {
+		in, out := &in.AddedPermissions, &out.AddedPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedPermissions != nil {
+		in, out := &in.RemovedPermissions, &out.RemovedPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PermissionPreview.
+func (in *PermissionPreview) DeepCopy() *PermissionPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(PermissionPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedBundle) DeepCopyInto(out *ResolvedBundle) {
+	*out = *in
+	out.BundleMetadata = in.BundleMetadata
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]BundleMetadata, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterProvidedAPIs != nil {
+		in, out := &in.ClusterProvidedAPIs, &out.ClusterProvidedAPIs
+		*out = make([]GroupVersionKind, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedBundle.
+func (in *ResolvedBundle) DeepCopy() *ResolvedBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountReference) DeepCopyInto(out *ServiceAccountReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountReference.
+func (in *ServiceAccountReference) DeepCopy() *ServiceAccountReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountReference)
+	in.DeepCopyInto(out)
+	return out
+}
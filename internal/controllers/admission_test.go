@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
 )
@@ -117,4 +118,12 @@ var _ = Describe("Operator Spec Validations", func() {
 		Expect(err).To(HaveOccurred(), "expected error for invalid channel length")
 		Expect(err.Error()).To(ContainSubstring("spec.channel: Too long: may not be longer than 48"))
 	})
+	It("should fail if a negative progress deadline is given", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName:             "package",
+			ProgressDeadlineSeconds: pointer.Int32(-1),
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for negative progress deadline")
+		Expect(err.Error()).To(ContainSubstring("spec.progressDeadlineSeconds in body should be greater than or equal to 0"))
+	})
 })
@@ -0,0 +1,91 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+// maxAvailableUpgradesPerChannel bounds the number of newer versions listed for each channel, so
+// that the status of an Operator far behind the latest versions stays small.
+const maxAvailableUpgradesPerChannel = 5
+
+// setAvailableUpgrades lists in the Operator status the versions of its package in each channel
+// that are newer than the bundle with the given image, the latest first and at most
+// maxAvailableUpgradesPerChannel of them, and sets the UpgradeAvailable condition accordingly.
+// The versions available in the channel of the resolved bundle are listed first.
+// Failing to determine the available upgrades does not prevent the installation, so the error
+// is only reported through the condition.
+func (r *OperatorReconciler) setAvailableUpgrades(ctx context.Context, op *operatorsv1alpha1.Operator, resolvedBundle *entity.BundleEntity, bundleImage string) {
+	currentChannel, err := resolvedBundle.ChannelName()
+	if err != nil {
+		op.Status.AvailableUpgrades = nil
+		setUpgradeAvailableStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return
+	}
+	newerBundles, err := r.Resolver.AvailableUpgrades(ctx, op.Spec.PackageName, bundleImage)
+	if err != nil {
+		op.Status.AvailableUpgrades = nil
+		setUpgradeAvailableStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return
+	}
+
+	// the newer bundles are ordered by channel and then from the highest version down, so the
+	// first bundles of each channel are its latest
+	var inCurrentChannel, inOtherChannels []operatorsv1alpha1.AvailableUpgrade
+	listedPerChannel := map[string]int{}
+	for _, bundle := range newerBundles {
+		version, err := bundle.Version()
+		if err != nil {
+			op.Status.AvailableUpgrades = nil
+			setUpgradeAvailableStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+			return
+		}
+		channel, err := bundle.ChannelName()
+		if err != nil {
+			op.Status.AvailableUpgrades = nil
+			setUpgradeAvailableStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+			return
+		}
+		if listedPerChannel[channel] == maxAvailableUpgradesPerChannel {
+			continue
+		}
+		listedPerChannel[channel]++
+		upgrade := operatorsv1alpha1.AvailableUpgrade{Version: version.String(), Channel: channel}
+		if channel == currentChannel {
+			inCurrentChannel = append(inCurrentChannel, upgrade)
+		} else {
+			inOtherChannels = append(inOtherChannels, upgrade)
+		}
+	}
+	op.Status.AvailableUpgrades = append(inCurrentChannel, inOtherChannels...)
+
+	if len(op.Status.AvailableUpgrades) == 0 {
+		setUpgradeAvailableStatusConditionUpToDate(&op.Status.Conditions, "no newer version is available", op.GetGeneration())
+		return
+	}
+	descriptions := make([]string, 0, len(op.Status.AvailableUpgrades))
+	for _, upgrade := range op.Status.AvailableUpgrades {
+		descriptions = append(descriptions, fmt.Sprintf("%s in channel %q", upgrade.Version, upgrade.Channel))
+	}
+	setUpgradeAvailableStatusConditionAvailable(&op.Status.Conditions, fmt.Sprintf("newer versions are available: %s", strings.Join(descriptions, ", ")), op.GetGeneration())
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/render"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+const (
+	// DefaultBundleManifestsNamespace is the namespace rukpak is installed in by default, which is the
+	// namespace it reads the ConfigMaps of bundle sources from.
+	DefaultBundleManifestsNamespace = "rukpak-system"
+
+	// bundleImageAnnotation records on a BundleDeployment the image of the bundle it installs when it
+	// installs manifests rendered by operator-controller instead of the bundle image itself.
+	bundleImageAnnotation = "operators.operatorframework.io/bundle-image"
+
+	// bundleManifestsPath is the directory of a plain bundle the provisioner reads manifests from.
+	bundleManifestsPath = "manifests"
+
+	// maxBundleManifestsSize bounds the manifests held by a single ConfigMap, as objects are limited to 1MiB.
+	maxBundleManifestsSize = 900 << 10
+)
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=create;delete
+
+// customizesInstall returns true if the Operator asks for the bundle to be installed differently from
+// how the registry provisioner installs it, in which case operator-controller renders the objects of
+// the bundle itself. The config of the install defaults alone only customizes the install of the
+// bundles whose Deployments it changes, see renderBundle.
+func customizesInstall(op *operatorsv1alpha1.Operator, installNamespace, bundleNamespace string) bool {
+	return installNamespace != bundleNamespace || len(op.Spec.WatchNamespaces) > 0 || op.Spec.Config != nil || len(op.Spec.Patches) > 0
+}
+
+// renderBundle returns the objects the bundle installs for the Operator, with the deployment config
+// applied but not the patches, and whether they are installed from manifests rendered by
+// operator-controller. They are rendered the way the Operator asks for them to be installed if it
+// customizes the install or if the effective config changes the Deployments of the bundle, otherwise
+// the registry provisioner installs the bundle image and they are rendered the way it does, for the
+// preflight checks and the permission preview to see what it installs. The objects are nil if
+// unknown, as the catalog does not provide all of them or as the registry provisioner does not
+// install them, in which case the config of the install defaults is not applied. It fails if the
+// install is customized but the catalog does not provide all the objects of the bundle, as the
+// bundle cannot be installed as asked without them.
+func renderBundle(op *operatorsv1alpha1.Operator, bundleEntity *entity.BundleEntity, installNamespace, bundleNamespace string) ([]unstructured.Unstructured, bool, error) {
+	objects, err := bundleObjects(bundleEntity)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read the objects of the bundle: %w", err)
+	}
+	if !customizesInstall(op, installNamespace, bundleNamespace) {
+		if len(objects) == 0 {
+			return nil, false, nil
+		}
+		rendered, err := render.RegistryV1(objects, bundleNamespace, nil)
+		if err != nil {
+			// the BundleDeployment reports why the registry provisioner does not install the bundle
+			return nil, false, nil
+		}
+		changed, err := changesDeployments(rendered, op.Status.EffectiveConfig)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to apply the deployment config: %w", err)
+		}
+		if !changed {
+			return rendered, false, nil
+		}
+		if err := applyDeploymentConfig(rendered, op.Status.EffectiveConfig); err != nil {
+			return nil, false, fmt.Errorf("failed to apply the deployment config: %w", err)
+		}
+		return rendered, true, nil
+	}
+	if len(objects) == 0 {
+		return nil, false, errors.New("the bundle cannot be installed as the operator configures it, as the catalog does not provide its objects")
+	}
+	rendered, err := render.RegistryV1(objects, installNamespace, op.Spec.WatchNamespaces)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to render the objects of the bundle: %w", err)
+	}
+	if err := applyDeploymentConfig(rendered, op.Status.EffectiveConfig); err != nil {
+		return nil, false, fmt.Errorf("failed to apply the deployment config: %w", err)
+	}
+	if installNamespace == bundleNamespace {
+		return rendered, true, nil
+	}
+	// the install namespace is created by operator-controller rather than by the bundle
+	kept := rendered[:0]
+	for _, obj := range rendered {
+		if obj.GetKind() == "Namespace" && obj.GetAPIVersion() == "v1" && obj.GetName() == installNamespace {
+			continue
+		}
+		kept = append(kept, obj)
+	}
+	return kept, true, nil
+}
+
+// bundleManifests returns the immutable ConfigMaps holding the manifests of the rendered objects of the
+// bundle, named after the Operator and their content, in the namespace the provisioner reads them from.
+func (r *OperatorReconciler) bundleManifests(op *operatorsv1alpha1.Operator, objects []unstructured.Unstructured) ([]corev1.ConfigMap, error) {
+	hash := sha256.New()
+	var files []map[string]string
+	size := 0
+	for i := range objects {
+		data, err := yaml.Marshal(objects[i].Object)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxBundleManifestsSize {
+			return nil, fmt.Errorf("%s %q is too large to be installed: %d bytes", objects[i].GetKind(), objects[i].GetName(), len(data))
+		}
+		if len(files) == 0 || size+len(data) > maxBundleManifestsSize {
+			files = append(files, map[string]string{})
+			size = 0
+		}
+		name := fmt.Sprintf("object-%d.yaml", i)
+		files[len(files)-1][name] = string(data)
+		size += len(data)
+		hash.Write([]byte(name))
+		hash.Write(data)
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))[:10]
+	configMaps := make([]corev1.ConfigMap, 0, len(files))
+	for i, data := range files {
+		configMaps = append(configMaps, corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s-%d", op.GetName(), digest, i),
+				Namespace: r.bundleManifestsNamespace(),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: operatorsv1alpha1.GroupVersion.String(),
+					Kind:       "Operator",
+					Name:       op.GetName(),
+					UID:        op.GetUID(),
+					Controller: pointer.Bool(true),
+				}},
+			},
+			Immutable: pointer.Bool(true),
+			Data:      data,
+		})
+	}
+	return configMaps, nil
+}
+
+// bundleManifestsNamespace returns the namespace of the ConfigMaps holding the manifests of bundles.
+func (r *OperatorReconciler) bundleManifestsNamespace() string {
+	if r.BundleManifestsNamespace == "" {
+		return DefaultBundleManifestsNamespace
+	}
+	return r.BundleManifestsNamespace
+}
+
+// ensureBundleManifests creates the ConfigMaps holding the manifests of the bundle, unless they already
+// exist, and returns the names of the ConfigMaps the named BundleDeployment reads manifests from that
+// are no longer needed once it reads them from the given ConfigMaps.
+func (r *OperatorReconciler) ensureBundleManifests(ctx context.Context, name string, configMaps []corev1.ConfigMap) ([]types.NamespacedName, error) {
+	existing := &rukpakv1alpha1.BundleDeployment{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, existing); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	names := sets.NewString()
+	for i := range configMaps {
+		names.Insert(configMaps[i].GetName())
+		if err := r.Client.Create(ctx, &configMaps[i]); client.IgnoreAlreadyExists(err) != nil {
+			return nil, fmt.Errorf("failed to create the manifests of the bundle: %w", err)
+		}
+	}
+	if existing.Spec.Template == nil {
+		return nil, nil
+	}
+	var stale []types.NamespacedName
+	for _, source := range existing.Spec.Template.Spec.Source.ConfigMaps {
+		if !names.Has(source.ConfigMap.Name) {
+			stale = append(stale, types.NamespacedName{Namespace: r.bundleManifestsNamespace(), Name: source.ConfigMap.Name})
+		}
+	}
+	return stale, nil
+}
+
+// deleteBundleManifests deletes the given ConfigMaps that held the manifests of a bundle. Those left
+// behind are deleted along with their Operator.
+func (r *OperatorReconciler) deleteBundleManifests(ctx context.Context, configMaps []types.NamespacedName) error {
+	for _, key := range configMaps {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+		if err := r.Client.Delete(ctx, cm); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete the previous manifests of the bundle: %w", err)
+		}
+	}
+	return nil
+}
+
+// bundleDeploymentImage returns the image of the bundle the BundleDeployment installs, or an empty
+// string if it does not install a bundle image.
+func bundleDeploymentImage(bd *rukpakv1alpha1.BundleDeployment) string {
+	if bd.Spec.Template == nil {
+		return ""
+	}
+	source := bd.Spec.Template.Spec.Source
+	switch source.Type {
+	case rukpakv1alpha1.SourceTypeImage:
+		if source.Image != nil {
+			return source.Image.Ref
+		}
+	case rukpakv1alpha1.SourceTypeConfigMaps:
+		return bd.GetAnnotations()[bundleImageAnnotation]
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	catalogd "github.com/operator-framework/catalogd/pkg/apis/core/v1beta1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+const (
+	// catalogEventCoalescePeriod is how long reconcile requests triggered by catalog content
+	// changes wait in the queue before being processed. Any further event for the same
+	// Operator within that window is folded into the pending request.
+	catalogEventCoalescePeriod = 5 * time.Second
+
+	// catalogEventQPS and catalogEventBurst bound the overall rate at which catalog content
+	// changes are allowed to enqueue Operators.
+	catalogEventQPS   = 10
+	catalogEventBurst = 100
+)
+
+// dependencyIndex keeps track of the packages each Operator's resolved bundle depends on,
+// so that changes to those packages' catalog content can be mapped back to the Operator.
+// The zero value is ready to use.
+type dependencyIndex struct {
+	mu       sync.RWMutex
+	packages map[string]sets.String
+}
+
+// set records the dependency packages of the named Operator, replacing any previous record.
+func (d *dependencyIndex) set(operatorName string, packageNames sets.String) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.packages == nil {
+		d.packages = map[string]sets.String{}
+	}
+	d.packages[operatorName] = packageNames
+}
+
+// delete forgets the dependency packages of the named Operator.
+func (d *dependencyIndex) delete(operatorName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.packages, operatorName)
+}
+
+// dependsOnAny returns true if the named Operator depends on any of the given packages.
+func (d *dependencyIndex) dependsOnAny(operatorName string, packageNames sets.String) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.packages[operatorName].HasAny(packageNames.UnsortedList()...)
+}
+
+// requestCoalescer adds reconcile requests to the queue after a delay. Requests that are already
+// waiting to be added are dropped, so a burst of events (e.g. a catalog refresh touching many
+// Packages and BundleMetadata) results in a single reconcile per affected Operator. The delay of
+// each newly added request is further extended by a shared token bucket, which keeps a large
+// refresh from flooding the queue all at once.
+type requestCoalescer struct {
+	delay   time.Duration
+	limiter workqueue.RateLimiter
+
+	mu sync.Mutex
+	// pending records until when each request waits to be added to the queue
+	pending map[reconcile.Request]time.Time
+}
+
+// add adds the requests that are not already pending to the queue.
+func (c *requestCoalescer) add(q workqueue.RateLimitingInterface, requests []reconcile.Request) {
+	if len(requests) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for req, until := range c.pending {
+		if !now.Before(until) {
+			delete(c.pending, req)
+		}
+	}
+	if c.pending == nil {
+		c.pending = map[reconcile.Request]time.Time{}
+	}
+	for _, req := range requests {
+		if _, ok := c.pending[req]; ok {
+			// the pending request will see the changes behind this event too
+			continue
+		}
+		// only newly added requests are charged to the shared token bucket
+		delay := c.delay + c.limiter.When(req)
+		c.pending[req] = now.Add(delay)
+		q.AddAfter(req, delay)
+	}
+}
+
+// coalescingEnqueueHandler is a handler.EventHandler that maps events to reconcile requests
+// like handler.EnqueueRequestsFromMapFunc does, but adds them to the queue through a
+// requestCoalescer, which may be shared by several handlers.
+type coalescingEnqueueHandler struct {
+	toRequests handler.MapFunc
+	coalescer  *requestCoalescer
+}
+
+var _ handler.EventHandler = &coalescingEnqueueHandler{}
+
+func (h *coalescingEnqueueHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, evt.Object)
+}
+
+func (h *coalescingEnqueueHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, evt.ObjectOld)
+	h.enqueue(q, evt.ObjectNew)
+}
+
+func (h *coalescingEnqueueHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, evt.Object)
+}
+
+func (h *coalescingEnqueueHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, evt.Object)
+}
+
+func (h *coalescingEnqueueHandler) enqueue(q workqueue.RateLimitingInterface, object client.Object) {
+	if object == nil {
+		return
+	}
+	h.coalescer.add(q, h.toRequests(object))
+}
+
+// operatorRequestsForCatalog generates reconcile requests for the Operators affected by a
+// change to a Catalog, i.e. those that may be affected by any of the packages it provides.
+func operatorRequestsForCatalog(ctx context.Context, c client.Reader, deps *dependencyIndex, logger logr.Logger) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		packages := catalogd.PackageList{}
+		if err := c.List(ctx, &packages); err != nil {
+			logger.Error(err, "unable to enqueue operators for catalog reconcile")
+			return nil
+		}
+		packageNames := sets.NewString()
+		for _, pkg := range packages.Items {
+			if pkg.Spec.Catalog.Name == object.GetName() {
+				packageNames.Insert(pkg.Spec.Name)
+			}
+		}
+		return operatorRequestsForPackages(ctx, c, deps, logger, packageNames)
+	}
+}
+
+// operatorRequestsForPackage generates reconcile requests for the Operators affected by a
+// change to a Package.
+func operatorRequestsForPackage(ctx context.Context, c client.Reader, deps *dependencyIndex, logger logr.Logger) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		pkg, ok := object.(*catalogd.Package)
+		if !ok {
+			return nil
+		}
+		return operatorRequestsForPackages(ctx, c, deps, logger, sets.NewString(pkg.Spec.Name))
+	}
+}
+
+// operatorRequestsForBundleMetadata generates reconcile requests for the Operators affected
+// by a change to a BundleMetadata.
+func operatorRequestsForBundleMetadata(ctx context.Context, c client.Reader, deps *dependencyIndex, logger logr.Logger) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		bundleMetadata, ok := object.(*catalogd.BundleMetadata)
+		if !ok {
+			return nil
+		}
+		return operatorRequestsForPackages(ctx, c, deps, logger, sets.NewString(bundleMetadata.Spec.Package))
+	}
+}
+
+// operatorRequestsForPackages generates reconcile requests for every Operator that either
+// requests one of the given packages, depends on one of them, or is not currently resolved.
+// Resolution is performed for all Operators at once, so an Operator that failed to resolve
+// may succeed after any content change.
+func operatorRequestsForPackages(ctx context.Context, c client.Reader, deps *dependencyIndex, logger logr.Logger, packageNames sets.String) []reconcile.Request {
+	operators := operatorsv1alpha1.OperatorList{}
+	if err := c.List(ctx, &operators); err != nil {
+		logger.Error(err, "unable to enqueue operators for catalog content reconcile")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, op := range operators.Items {
+		if !packageNames.Has(op.Spec.PackageName) &&
+			!deps.dependsOnAny(op.GetName(), packageNames) &&
+			apimeta.IsStatusConditionTrue(op.Status.Conditions, operatorsv1alpha1.TypeResolved) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: op.GetNamespace(),
+				Name:      op.GetName(),
+			},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	catalogd "github.com/operator-framework/catalogd/pkg/apis/core/v1beta1"
+)
+
+var _ = Describe("Catalog event coalescing", func() {
+	var (
+		queue   *recordingQueue
+		limiter *countingLimiter
+		names   []string
+		handler *coalescingEnqueueHandler
+		catalog *catalogd.Catalog
+	)
+	BeforeEach(func() {
+		queue = &recordingQueue{}
+		limiter = &countingLimiter{}
+		names = []string{"op-a"}
+		handler = &coalescingEnqueueHandler{
+			toRequests: func(client.Object) []reconcile.Request {
+				var requests []reconcile.Request
+				for _, name := range names {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+				}
+				return requests
+			},
+			coalescer: &requestCoalescer{delay: time.Minute, limiter: limiter},
+		}
+		catalog = &catalogd.Catalog{}
+	})
+
+	It("enqueues a request once and charges the limiter once for duplicate events", func() {
+		for i := 0; i < 5; i++ {
+			handler.Update(event.UpdateEvent{ObjectOld: catalog, ObjectNew: catalog}, queue)
+		}
+		Expect(queue.added).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: "op-a"}}))
+		Expect(limiter.charged).To(Equal(1))
+	})
+
+	It("enqueues and charges each newly affected operator", func() {
+		handler.Create(event.CreateEvent{Object: catalog}, queue)
+		names = []string{"op-a", "op-b"}
+		handler.Create(event.CreateEvent{Object: catalog}, queue)
+		Expect(queue.added).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "op-a"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "op-b"}},
+		))
+		Expect(limiter.charged).To(Equal(2))
+	})
+
+	It("shares pending requests between handlers using the same coalescer", func() {
+		other := &coalescingEnqueueHandler{toRequests: handler.toRequests, coalescer: handler.coalescer}
+		handler.Create(event.CreateEvent{Object: catalog}, queue)
+		other.Create(event.CreateEvent{Object: catalog}, queue)
+		Expect(queue.added).To(HaveLen(1))
+		Expect(limiter.charged).To(Equal(1))
+	})
+
+	It("enqueues the request again once the pending one was added to the queue", func() {
+		handler.coalescer.delay = 0
+		handler.Create(event.CreateEvent{Object: catalog}, queue)
+		handler.Create(event.CreateEvent{Object: catalog}, queue)
+		Expect(queue.added).To(HaveLen(2))
+		Expect(limiter.charged).To(Equal(2))
+	})
+})
+
+// recordingQueue records the requests added to it after a delay.
+type recordingQueue struct {
+	workqueue.RateLimitingInterface
+	added []interface{}
+}
+
+func (q *recordingQueue) AddAfter(item interface{}, _ time.Duration) {
+	q.added = append(q.added, item)
+}
+
+// countingLimiter counts how many times it was asked for a delay.
+type countingLimiter struct {
+	charged int
+}
+
+func (l *countingLimiter) When(interface{}) time.Duration {
+	l.charged++
+	return 0
+}
+
+func (l *countingLimiter) Forget(interface{}) {}
+
+func (l *countingLimiter) NumRequeues(interface{}) int { return 0 }
@@ -0,0 +1,188 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// effectiveDeploymentConfig returns the configuration applied to the Deployments of the bundle of
+// the Operator: its config merged over the config of the install defaults, if any. It returns nil if
+// the Deployments are installed as the bundle defines them.
+func effectiveDeploymentConfig(op *operatorsv1alpha1.Operator, defaults *operatorsv1alpha1.InstallDefaults) *operatorsv1alpha1.DeploymentConfig {
+	if defaults == nil || defaults.Spec.Config == nil {
+		return op.Spec.Config.DeepCopy()
+	}
+	if op.Spec.Config == nil {
+		return defaults.Spec.Config.DeepCopy()
+	}
+	return mergeDeploymentConfig(defaults.Spec.Config, op.Spec.Config)
+}
+
+// mergeDeploymentConfig merges the override config over the base config, as documented by the
+// InstallDefaults.
+func mergeDeploymentConfig(base, override *operatorsv1alpha1.DeploymentConfig) *operatorsv1alpha1.DeploymentConfig {
+	merged := base.DeepCopy()
+	override = override.DeepCopy()
+
+	for _, env := range override.Env {
+		merged.Env = replaceOrAppend(merged.Env, env, func(e corev1.EnvVar) bool { return e.Name == env.Name })
+	}
+	merged.EnvFrom = append(merged.EnvFrom, override.EnvFrom...)
+
+	if override.Resources != nil {
+		if merged.Resources == nil {
+			merged.Resources = &corev1.ResourceRequirements{}
+		}
+		merged.Resources.Requests = mergeResourceList(merged.Resources.Requests, override.Resources.Requests)
+		merged.Resources.Limits = mergeResourceList(merged.Resources.Limits, override.Resources.Limits)
+	}
+
+	for _, toleration := range override.Tolerations {
+		merged.Tolerations = replaceOrAppend(merged.Tolerations, toleration, func(t corev1.Toleration) bool {
+			return equality.Semantic.DeepEqual(t, toleration)
+		})
+	}
+
+	if override.NodeSelector != nil {
+		merged.NodeSelector = override.NodeSelector
+	}
+
+	for _, volume := range override.Volumes {
+		merged.Volumes = replaceOrAppend(merged.Volumes, volume, func(v corev1.Volume) bool { return v.Name == volume.Name })
+	}
+	for _, mount := range override.VolumeMounts {
+		merged.VolumeMounts = replaceOrAppend(merged.VolumeMounts, mount, func(m corev1.VolumeMount) bool { return m.MountPath == mount.MountPath })
+	}
+	return merged
+}
+
+// replaceOrAppend replaces the first item of the list that matches with the given item, or appends
+// the item if none matches.
+func replaceOrAppend[T any](list []T, item T, matches func(T) bool) []T {
+	for i := range list {
+		if matches(list[i]) {
+			list[i] = item
+			return list
+		}
+	}
+	return append(list, item)
+}
+
+// mergeResourceList merges the override quantities over the base ones.
+func mergeResourceList(base, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return base
+	}
+	if base == nil {
+		base = corev1.ResourceList{}
+	}
+	for name, quantity := range override {
+		base[name] = quantity
+	}
+	return base
+}
+
+// applyDeploymentConfig applies the configuration to the Deployments among the rendered objects of
+// a bundle, as documented by the DeploymentConfig.
+func applyDeploymentConfig(objects []unstructured.Unstructured, config *operatorsv1alpha1.DeploymentConfig) error {
+	if config == nil {
+		return nil
+	}
+	for i := range objects {
+		gvk := objects[i].GroupVersionKind()
+		if gvk.Group != appsv1.GroupName || gvk.Kind != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(objects[i].Object, deployment); err != nil {
+			return fmt.Errorf("failed to read Deployment %q: %w", objects[i].GetName(), err)
+		}
+		configurePodSpec(&deployment.Spec.Template.Spec, config)
+		fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+		if err != nil {
+			return fmt.Errorf("failed to configure Deployment %q: %w", objects[i].GetName(), err)
+		}
+		objects[i].Object = fields
+	}
+	return nil
+}
+
+// changesDeployments returns true if applying the configuration changes the pods of any of the
+// Deployments among the objects.
+func changesDeployments(objects []unstructured.Unstructured, config *operatorsv1alpha1.DeploymentConfig) (bool, error) {
+	if config == nil {
+		return false, nil
+	}
+	for i := range objects {
+		gvk := objects[i].GroupVersionKind()
+		if gvk.Group != appsv1.GroupName || gvk.Kind != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(objects[i].Object, deployment); err != nil {
+			return false, fmt.Errorf("failed to read Deployment %q: %w", objects[i].GetName(), err)
+		}
+		pod := deployment.Spec.Template.Spec.DeepCopy()
+		configurePodSpec(pod, config)
+		if !equality.Semantic.DeepEqual(pod, &deployment.Spec.Template.Spec) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// configurePodSpec applies the configuration to the pods of a Deployment and to their containers.
+func configurePodSpec(pod *corev1.PodSpec, config *operatorsv1alpha1.DeploymentConfig) {
+	config = config.DeepCopy()
+	for _, toleration := range config.Tolerations {
+		pod.Tolerations = replaceOrAppend(pod.Tolerations, toleration, func(t corev1.Toleration) bool {
+			return equality.Semantic.DeepEqual(t, toleration)
+		})
+	}
+	if config.NodeSelector != nil {
+		pod.NodeSelector = config.NodeSelector
+	}
+	for _, volume := range config.Volumes {
+		pod.Volumes = replaceOrAppend(pod.Volumes, volume, func(v corev1.Volume) bool { return v.Name == volume.Name })
+	}
+	for i := range pod.Containers {
+		container := &pod.Containers[i]
+		for _, env := range config.Env {
+			container.Env = replaceOrAppend(container.Env, env, func(e corev1.EnvVar) bool { return e.Name == env.Name })
+		}
+		for _, envFrom := range config.EnvFrom {
+			container.EnvFrom = replaceOrAppend(container.EnvFrom, envFrom, func(e corev1.EnvFromSource) bool {
+				return equality.Semantic.DeepEqual(e, envFrom)
+			})
+		}
+		if config.Resources != nil {
+			container.Resources = *config.Resources.DeepCopy()
+		}
+		for _, mount := range config.VolumeMounts {
+			container.VolumeMounts = replaceOrAppend(container.VolumeMounts, mount, func(m corev1.VolumeMount) bool { return m.MountPath == mount.MountPath })
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=installdefaults,verbs=get;list;watch
+
+// installDefaults returns the InstallDefaults singleton, or nil if it does not exist.
+func (r *OperatorReconciler) installDefaults(ctx context.Context) (*operatorsv1alpha1.InstallDefaults, error) {
+	defaults := &operatorsv1alpha1.InstallDefaults{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: operatorsv1alpha1.InstallDefaultsName}, defaults); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// operatorRequestsForInstallDefaults generates reconcile requests for every Operator when the
+// InstallDefaults singleton changes.
+func operatorRequestsForInstallDefaults(ctx context.Context, c client.Reader, logger logr.Logger) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		if object.GetName() != operatorsv1alpha1.InstallDefaultsName {
+			return nil
+		}
+		operators := operatorsv1alpha1.OperatorList{}
+		if err := c.List(ctx, &operators); err != nil {
+			logger.Error(err, "unable to enqueue operators for install defaults change")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(operators.Items))
+		for _, op := range operators.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: op.GetName()}})
+		}
+		return requests
+	}
+}
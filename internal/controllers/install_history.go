@@ -0,0 +1,105 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// maxInstallHistory is the number of revisions kept in the install history of an Operator.
+const maxInstallHistory = 10
+
+// recordInstallHistory appends a revision to the install history of the Operator whenever the
+// resolved bundle is installed, or fails to install. Installing a bundle marks the previously
+// installed bundle as replaced.
+func recordInstallHistory(op *operatorsv1alpha1.Operator) {
+	resolved := op.Status.ResolvedBundle
+	if resolved == nil {
+		return
+	}
+	now := metav1.Now()
+	latest := latestInstallRevision(op.Status.InstallHistory)
+
+	if apimeta.IsStatusConditionTrue(op.Status.Conditions, operatorsv1alpha1.TypeInstalled) {
+		current := currentInstallRevision(op.Status.InstallHistory)
+		if current != nil && current.Image == resolved.Image {
+			return
+		}
+		if current != nil {
+			current.ReplaceTime = &now
+		}
+		// the installation may succeed after it was found to have failed, e.g. once the
+		// BundleDeployment is fixed up, in which case the failed revision is superseded
+		if latest != nil && latest.Outcome == operatorsv1alpha1.InstallOutcomeFailed && latest.Image == resolved.Image {
+			latest.BundleMetadata = resolved.BundleMetadata
+			latest.InstallTime = now
+			latest.Outcome = operatorsv1alpha1.InstallOutcomeSucceeded
+			latest.Message = ""
+			return
+		}
+		appendInstallRevision(op, operatorsv1alpha1.InstallRevision{
+			BundleMetadata: resolved.BundleMetadata,
+			InstallTime:    now,
+			Outcome:        operatorsv1alpha1.InstallOutcomeSucceeded,
+		})
+		return
+	}
+
+	failure := installFailure(op)
+	if failure == nil || (latest != nil && latest.Image == resolved.Image) {
+		return
+	}
+	appendInstallRevision(op, operatorsv1alpha1.InstallRevision{
+		BundleMetadata: resolved.BundleMetadata,
+		InstallTime:    now,
+		Outcome:        operatorsv1alpha1.InstallOutcomeFailed,
+		Message:        failure.Message,
+	})
+}
+
+// latestInstallRevision returns the most recent revision in the install history, if any.
+func latestInstallRevision(history []operatorsv1alpha1.InstallRevision) *operatorsv1alpha1.InstallRevision {
+	if len(history) == 0 {
+		return nil
+	}
+	return &history[len(history)-1]
+}
+
+// currentInstallRevision returns the revision of the bundle that is currently installed, if any.
+func currentInstallRevision(history []operatorsv1alpha1.InstallRevision) *operatorsv1alpha1.InstallRevision {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Outcome == operatorsv1alpha1.InstallOutcomeSucceeded {
+			if history[i].ReplaceTime != nil {
+				return nil
+			}
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+// appendInstallRevision appends the revision to the install history, dropping the oldest
+// revisions beyond maxInstallHistory.
+func appendInstallRevision(op *operatorsv1alpha1.Operator, revision operatorsv1alpha1.InstallRevision) {
+	op.Status.InstallHistory = append(op.Status.InstallHistory, revision)
+	if excess := len(op.Status.InstallHistory) - maxInstallHistory; excess > 0 {
+		op.Status.InstallHistory = op.Status.InstallHistory[excess:]
+	}
+}
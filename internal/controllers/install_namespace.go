@@ -0,0 +1,112 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+const (
+	// installNamespaceOwnerLabel marks the namespaces created by operator-controller with the name
+	// of the Operator they were created for.
+	installNamespaceOwnerLabel = "operators.operatorframework.io/install-namespace-owner"
+
+	// installNamespaceFinalizer keeps a deleted Operator around until the install namespace that was
+	// created for it is deleted.
+	installNamespaceFinalizer = "operators.operatorframework.io/delete-install-namespace"
+)
+
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create;delete
+
+// installNamespaces returns the namespace the bundle of the Operator is installed in, defaulted from
+// the bundle metadata, and the namespace the bundle creates itself when it is installed: the namespace
+// it suggests, or <packageName>-system if it suggests none.
+func installNamespaces(op *operatorsv1alpha1.Operator, bundleEntity *entity.BundleEntity) (string, string, error) {
+	bundleNamespace, err := bundleEntity.DefaultInstallNamespace()
+	if err != nil {
+		return "", "", err
+	}
+	if op.Spec.InstallNamespace != "" {
+		return op.Spec.InstallNamespace, bundleNamespace, nil
+	}
+	return bundleNamespace, bundleNamespace, nil
+}
+
+// ensureInstallNamespace creates the install namespace of the Operator, labeled with the name of the
+// Operator, unless it already exists or is created by the bundle itself.
+func (r *OperatorReconciler) ensureInstallNamespace(ctx context.Context, op *operatorsv1alpha1.Operator, installNamespace, bundleNamespace string) error {
+	if installNamespace == bundleNamespace {
+		return nil
+	}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: installNamespace}, &corev1.Namespace{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   installNamespace,
+			Labels: map[string]string{installNamespaceOwnerLabel: op.GetName()},
+		},
+	}
+	if err := r.Client.Create(ctx, ns); client.IgnoreAlreadyExists(err) != nil {
+		return fmt.Errorf("failed to create install namespace %q: %w", installNamespace, err)
+	}
+	return nil
+}
+
+// finalizeInstallNamespace deletes the install namespace of a deleted Operator if it was created for
+// the Operator, and then lets the Operator go.
+func (r *OperatorReconciler) finalizeInstallNamespace(ctx context.Context, op *operatorsv1alpha1.Operator) error {
+	if !controllerutil.ContainsFinalizer(op, installNamespaceFinalizer) {
+		return nil
+	}
+	if op.Status.InstallNamespace != "" {
+		ns := &corev1.Namespace{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: op.Status.InstallNamespace}, ns)
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		if err == nil && ns.GetLabels()[installNamespaceOwnerLabel] == op.GetName() {
+			if err := r.Client.Delete(ctx, ns); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete install namespace %q: %w", op.Status.InstallNamespace, err)
+			}
+		}
+	}
+	controllerutil.RemoveFinalizer(op, installNamespaceFinalizer)
+	return nil
+}
+
+// updateInstallNamespaceFinalizer adds the finalizer deleting the install namespace to the Operator
+// if it asks for the namespace to be deleted along with it, and removes it otherwise.
+func updateInstallNamespaceFinalizer(op *operatorsv1alpha1.Operator) {
+	if op.Spec.DeleteInstallNamespace {
+		controllerutil.AddFinalizer(op, installNamespaceFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(op, installNamespaceFinalizer)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// progressDeadline returns how long the Operator's bundle may take to be installed,
+// or zero if the installation has no deadline.
+func (r *OperatorReconciler) progressDeadline(op *operatorsv1alpha1.Operator, defaults *operatorsv1alpha1.InstallDefaults) time.Duration {
+	if op.Spec.ProgressDeadlineSeconds != nil {
+		return time.Duration(*op.Spec.ProgressDeadlineSeconds) * time.Second
+	}
+	if defaults != nil && defaults.Spec.ProgressDeadlineSeconds != nil {
+		return time.Duration(*defaults.Spec.ProgressDeadlineSeconds) * time.Second
+	}
+	return r.DefaultProgressDeadline
+}
+
+// trackInstallAttempt records the progress of the attempt to install the given bundle, and
+// fails the installation for good once it has not completed within the progress deadline, whether
+// the bundle is still being installed or is held back by the preflight checks.
+// A new attempt starts whenever the bundle or the generation of the Operator spec changes.
+// The returned result requeues the Operator when the deadline is due.
+func (r *OperatorReconciler) trackInstallAttempt(op *operatorsv1alpha1.Operator, defaults *operatorsv1alpha1.InstallDefaults, bundleImage string, result ctrl.Result) ctrl.Result {
+	now := metav1.Now()
+	attempt := op.Status.InstallAttempt
+	if attempt == nil || attempt.BundleResource != bundleImage || attempt.Generation != op.GetGeneration() {
+		attempt = &operatorsv1alpha1.InstallAttempt{
+			BundleResource: bundleImage,
+			Generation:     op.GetGeneration(),
+			StartTime:      now,
+		}
+		op.Status.InstallAttempt = attempt
+	}
+
+	if attempt.CompletionTime != nil {
+		return result
+	}
+	if attempt.FailureTime == nil && apimeta.IsStatusConditionTrue(op.Status.Conditions, operatorsv1alpha1.TypeInstalled) {
+		attempt.CompletionTime = &now
+		return result
+	}
+
+	deadline := r.progressDeadline(op, defaults)
+	if attempt.FailureTime == nil {
+		if deadline <= 0 {
+			return result
+		}
+		remaining := attempt.StartTime.Add(deadline).Sub(now.Time)
+		if remaining > 0 {
+			if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+				result.RequeueAfter = remaining
+			}
+			return result
+		}
+		attempt.FailureTime = &now
+	}
+
+	message := fmt.Sprintf("installation of %q did not complete within the progress deadline", bundleImage)
+	op.Status.InstalledBundleResource = ""
+	setInstalledStatusConditionDeadlineExceeded(&op.Status.Conditions, message, op.GetGeneration())
+	setProgressingStatusConditionDeadlineExceeded(&op.Status.Conditions, message, op.GetGeneration())
+	return result
+}
@@ -18,29 +18,40 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
-	"github.com/go-logr/logr"
 	catalogd "github.com/operator-framework/catalogd/pkg/apis/core/v1beta1"
+	"github.com/operator-framework/deppy/pkg/deppy"
 	"github.com/operator-framework/deppy/pkg/deppy/solver"
 	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	"golang.org/x/time/rate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
 	"github.com/operator-framework/operator-controller/internal/controllers/validators"
+	"github.com/operator-framework/operator-controller/internal/preflight"
 	"github.com/operator-framework/operator-controller/internal/resolution"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
@@ -51,6 +62,23 @@ type OperatorReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Resolver *resolution.OperatorResolver
+
+	// DefaultProgressDeadline is how long the bundle of an Operator that does not specify
+	// its own progress deadline may take to be installed. Zero means no deadline.
+	DefaultProgressDeadline time.Duration
+
+	// PreflightChecks must pass before the BundleDeployment of an Operator is created or
+	// updated. If nil, the built-in preflight checks are used.
+	PreflightChecks []preflight.Check
+
+	// BundleManifestsNamespace is the namespace of the ConfigMaps holding the manifests of the bundles
+	// rendered by operator-controller, which must be the namespace rukpak reads bundle sources from.
+	// Defaults to DefaultBundleManifestsNamespace.
+	BundleManifestsNamespace string
+
+	// dependencies tracks the packages each Operator's resolved bundle depends on,
+	// so that catalog content changes can be mapped to the affected Operators.
+	dependencies dependencyIndex
 }
 
 //+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operators,verbs=get;list;watch
@@ -70,6 +98,9 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	var existingOp = &operatorsv1alpha1.Operator{}
 	if err := r.Get(ctx, req.NamespacedName, existingOp); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.dependencies.delete(req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -109,24 +140,48 @@ func checkForUnexpectedFieldChange(a, b operatorsv1alpha1.Operator) bool {
 
 // Helper function to do the actual reconcile
 func (r *OperatorReconciler) reconcile(ctx context.Context, op *operatorsv1alpha1.Operator) (ctrl.Result, error) {
+	// Deleted Operators are only reconciled to delete the namespace created for them, if asked to.
+	if !op.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, r.finalizeInstallNamespace(ctx, op)
+	}
+	updateInstallNamespaceFinalizer(op)
+
+	op.Status.ObservedGeneration = op.GetGeneration()
+
 	// validate spec
 	if err := validators.ValidateOperatorSpec(op); err != nil {
-		// Set the TypeInstalled condition to Unknown to indicate that the resolution
-		// hasn't been attempted yet, due to the spec being invalid.
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as spec is invalid", op.GetGeneration())
-		// Set the TypeResolved condition to Unknown to indicate that the resolution
-		// hasn't been attempted yet, due to the spec being invalid.
-		op.Status.ResolvedBundleResource = ""
+		// Set the TypeInstalled and TypeResolved conditions to Unknown to indicate that the
+		// resolution hasn't been attempted yet, due to the spec being invalid.
+		resetStatusOnError(op, "spec is invalid")
+		op.Status.EffectiveConfig = nil
+		op.Status.EffectivePullSecret = ""
+		op.Status.EffectiveProgressDeadlineSeconds = nil
 		setResolvedStatusConditionUnknown(&op.Status.Conditions, "validation has not been attempted as spec is invalid", op.GetGeneration())
 		return ctrl.Result{}, nil
 	}
+
+	// read the install defaults the Operator is configured over
+	defaults, err := r.installDefaults(ctx)
+	if err != nil {
+		resetStatusOnError(op, "the install defaults could not be read")
+		setResolvedStatusConditionUnknown(&op.Status.Conditions, "resolution has not been attempted as the install defaults could not be read", op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+	op.Status.EffectiveConfig = effectiveDeploymentConfig(op, defaults)
+	op.Status.EffectivePullSecret = pullSecret(op, defaults)
+	op.Status.EffectiveProgressDeadlineSeconds = pointer.Int32(int32(r.progressDeadline(op, defaults) / time.Second))
+
 	// run resolution
 	solution, err := r.Resolver.Resolve(ctx)
+	if err == nil {
+		// report why no solution was found, e.g. a conflict with a CRD that is not owned by operator-controller
+		var unsat deppy.NotSatisfiable
+		if errors.As(solution.Error(), &unsat) && len(unsat) > 0 {
+			err = unsat
+		}
+	}
 	if err != nil {
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
-		op.Status.ResolvedBundleResource = ""
+		resetStatusOnError(op, "resolution failed")
 		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
 		return ctrl.Result{}, err
 	}
@@ -135,53 +190,249 @@ func (r *OperatorReconciler) reconcile(ctx context.Context, op *operatorsv1alpha
 	// Operator's desired package name.
 	bundleEntity, err := r.getBundleEntityFromSolution(solution, op.Spec.PackageName)
 	if err != nil {
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
-		op.Status.ResolvedBundleResource = ""
+		resetStatusOnError(op, "resolution failed")
+		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+
+	// Remember which packages the resolved bundle depends on, so that changes to their
+	// catalog content trigger a new reconcile of this Operator.
+	dependencyBundles := r.getDependencyBundlesFromSolution(solution, bundleEntity)
+	clusterProvidedGVKs := r.getClusterProvidedGVKsFromSolution(solution, bundleEntity, dependencyBundles)
+	dependencyPackages, err := packageNames(dependencyBundles)
+	if err != nil {
+		resetStatusOnError(op, "resolution failed")
 		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
 		return ctrl.Result{}, err
 	}
+	r.dependencies.set(op.GetName(), dependencyPackages)
 
 	// Get the bundle image reference for the bundle
 	bundleImage, err := bundleEntity.BundlePath()
 	if err != nil {
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
-
-		op.Status.ResolvedBundleResource = ""
+		resetStatusOnError(op, "resolution failed")
 		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
 		return ctrl.Result{}, err
 	}
 
+	// Hold back the upgrade if the currently installed bundle reports that it is not ready for it,
+	// unless the user forces the upgrade.
+	var result ctrl.Result
+	resolvedEntity := bundleEntity
+	installedImage, err := r.installedBundleImage(ctx, op.GetName())
+	if err != nil {
+		// the error is likely transient, and says nothing about the installed bundle
+		setUpgradeBlockedStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+	if installedImage == "" || installedImage == bundleImage {
+		setUpgradeBlockedStatusConditionAllowed(&op.Status.Conditions, "no upgrade is pending", op.GetGeneration())
+	} else {
+		notUpgradeable, err := r.upgradeNotReadyCondition(ctx, op.GetName())
+		if err != nil {
+			// the error is likely transient, and says nothing about the installed bundle
+			setUpgradeBlockedStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+			return ctrl.Result{}, err
+		}
+		switch {
+		case notUpgradeable == nil:
+			setUpgradeBlockedStatusConditionAllowed(&op.Status.Conditions, fmt.Sprintf("upgrading from %q to %q", installedImage, bundleImage), op.GetGeneration())
+		case isForcedUpgrade(op, bundleEntity):
+			setUpgradeBlockedStatusConditionForced(&op.Status.Conditions, fmt.Sprintf("upgrading from %q to %q although the installed bundle is not upgradeable: %s", installedImage, bundleImage, notUpgradeable.Message), op.GetGeneration())
+		default:
+			blocked := fmt.Sprintf("upgrade from %q to %q is blocked as the installed bundle is not upgradeable: %s", installedImage, bundleImage, notUpgradeable.Message)
+			installedEntity, err := r.Resolver.BundleByPath(ctx, op.Spec.PackageName, installedImage)
+			if err != nil {
+				// Without its entity the installed bundle cannot be installed again as it is, so
+				// its BundleDeployment is left untouched until the catalogs provide the bundle.
+				resetStatusOnError(op, "the installed bundle could not be found")
+				setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+				setUpgradeBlockedStatusConditionBlocked(&op.Status.Conditions, fmt.Sprintf("%s; the installed bundle is left as it is: %v", blocked, err), op.GetGeneration())
+				return ctrl.Result{}, err
+			}
+			setUpgradeBlockedStatusConditionBlocked(&op.Status.Conditions, blocked, op.GetGeneration())
+			bundleImage = installedImage
+			result.RequeueAfter = upgradeReadinessRecheckInterval
+			resolvedEntity = installedEntity
+			dependencyBundles = nil
+			clusterProvidedGVKs = nil
+		}
+	}
+
+	// Let the user know about newer versions of the package, which may be held back by the
+	// constraints of the Operator or by the installed bundle.
+	r.setAvailableUpgrades(ctx, op, bundleEntity, bundleImage)
+
+	// Describe the resolved bundle and where it comes from.
+	resolvedBundle, err := resolvedBundleStatus(resolvedEntity, dependencyBundles, clusterProvidedGVKs)
+	if err != nil {
+		resetStatusOnError(op, "resolution failed")
+		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return result, err
+	}
+
+	// Determine the namespace the bundle is installed in, which the bundle may suggest.
+	installNamespace, bundleNamespace, err := installNamespaces(op, resolvedEntity)
+	if err != nil {
+		resetStatusOnError(op, "resolution failed")
+		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return result, err
+	}
+	op.Status.InstallNamespace = installNamespace
+
 	// Now we can set the Resolved Condition, and the resolvedBundleSource field to the bundleImage value.
 	op.Status.ResolvedBundleResource = bundleImage
+	op.Status.ResolvedBundle = resolvedBundle
 	setResolvedStatusConditionSuccess(&op.Status.Conditions, fmt.Sprintf("resolved to %q", bundleImage), op.GetGeneration())
 
 	// Ensure a BundleDeployment exists with its bundle source from the bundle
-	// image we just looked up in the solution.
-	dep := r.generateExpectedBundleDeployment(*op, bundleImage)
+	// image we just looked up in the solution, once it passes the preflight checks.
+	// Render the bundle if the Operator asks for it to be installed differently from how the registry
+	// provisioner installs the bundle image.
+	message := "installation has not been attempted as the bundle cannot be installed as configured"
+	objects, rendered, err := renderBundle(op, resolvedEntity, installNamespace, bundleNamespace)
+	if err != nil {
+		resetInstallStatus(op, message)
+		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		setPreflightPassedStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+		setPatchesAppliedStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+		op.Status.PermissionPreview = nil
+		return result, nil
+	}
+	if objects == nil {
+		// the config of the install defaults cannot be applied to objects that are unknown
+		op.Status.EffectiveConfig = nil
+	}
+	if rendered {
+		// the rendered objects are installed from ConfigMaps, no image is pulled
+		op.Status.EffectivePullSecret = ""
+	}
+	// The patches are applied last, so that they can change anything operator-controller renders.
+	objects, err = applyPatches(op, objects)
+	var manifests []corev1.ConfigMap
+	if err == nil && rendered {
+		manifests, err = r.bundleManifests(op, objects)
+	}
+	if err != nil {
+		resetInstallStatus(op, message)
+		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		setPreflightPassedStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+		op.Status.PermissionPreview = nil
+		return result, nil
+	}
+
+	// Preview the permissions the objects of the bundle grant to its operator before anything is applied.
+	r.setPermissionPreview(ctx, op, bundleImage, objects, installedImage)
+
+	dep := r.generateExpectedBundleDeployment(*op, bundleImage, manifests, op.Status.EffectivePullSecret)
+	applied, err := r.bundleDeploymentApplied(ctx, dep)
+	if err != nil {
+		// the error is likely transient, and says nothing about the installed bundle
+		setPreflightPassedStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return result, err
+	}
+	if applied {
+		keepPreflightPassedCondition(op)
+	} else if !r.runPreflightChecks(ctx, op, dep, objects) {
+		resetInstallStatus(op, "installation has not been attempted as preflight checks failed")
+		if result.RequeueAfter == 0 || preflightRecheckInterval < result.RequeueAfter {
+			result.RequeueAfter = preflightRecheckInterval
+		}
+		// an installation held back by the preflight checks is given up on like any other
+		return r.trackInstallAttempt(op, defaults, bundleImage, result), nil
+	}
+	if err := r.ensureInstallNamespace(ctx, op, installNamespace, bundleNamespace); err != nil {
+		resetInstallStatus(op, err.Error())
+		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return result, err
+	}
+	staleManifests, err := r.ensureBundleManifests(ctx, op.GetName(), manifests)
+	if err != nil {
+		resetInstallStatus(op, err.Error())
+		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return result, err
+	}
 	if err := r.ensureBundleDeployment(ctx, dep); err != nil {
 		// originally Reason: operatorsv1alpha1.ReasonInstallationFailed
-		op.Status.InstalledBundleResource = ""
+		resetInstallStatus(op, err.Error())
 		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+		return result, err
+	}
+	if err := r.deleteBundleManifests(ctx, staleManifests); err != nil {
+		// the BundleDeployment no longer reads them, and they are deleted along with the Operator anyway
+		log.FromContext(ctx).Error(err, "failed to prune the manifests of the bundle")
 	}
 
 	// convert existing unstructured object into bundleDeployment for easier mapping of status.
 	existingTypedBundleDeployment := &rukpakv1alpha1.BundleDeployment{}
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(dep.UnstructuredContent(), existingTypedBundleDeployment); err != nil {
 		// originally Reason: operatorsv1alpha1.ReasonInstallationStatusUnknown
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+		resetInstallStatus(op, err.Error())
+		return result, err
 	}
-
 	// Let's set the proper Installed condition and InstalledBundleResource field based on the
 	// existing BundleDeployment object status.
 	mapBDStatusToInstalledCondition(existingTypedBundleDeployment, op)
 
+	// Report whether the BundleDeployment is still rolling out the bundle.
+	mapBDStatusToProgressingCondition(existingTypedBundleDeployment, op)
+
+	// Report a bundle image that cannot be pulled for lack of valid credentials, which rukpak keeps
+	// trying to pull, as a failed installation that needs a pull secret.
+	pullFailure, err := r.imagePullAuthFailure(ctx, existingTypedBundleDeployment)
+	if err != nil {
+		// the error is likely transient, and says nothing about the installed bundle
+		return result, err
+	}
+	if pullFailure != "" {
+		op.Status.InstalledBundleResource = ""
+		setInstalledStatusConditionImagePullUnauthorized(&op.Status.Conditions, pullFailure, op.GetGeneration())
+		setProgressingStatusConditionImagePullUnauthorized(&op.Status.Conditions, pullFailure, op.GetGeneration())
+	}
+
+	// Give up on installations that do not complete within the progress deadline.
+	result = r.trackInstallAttempt(op, defaults, bundleImage, result)
+
+	// Check the health of the workloads the bundle installed, for as long as it is installed.
+	result, err = r.setHealthyCondition(ctx, op, resolvedEntity, result)
+	if err != nil {
+		return result, err
+	}
+
+	// Roll back from bundles that fail to install or to become healthy, if the Operator opted in.
+	result = trackRollback(op, result)
+
+	// Keep a record of the bundles the Operator installed over time.
+	recordInstallHistory(op)
+
 	// set the status of the operator based on the respective bundle deployment status conditions.
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// resetStatusOnError reports that the reconciliation of the Operator stopped for the given reason,
+// e.g. "resolution failed", before its bundle was resolved: nothing is installed, and the status
+// describing the resolved bundle and its upgrades is cleared. The Resolved condition is left to the
+// caller, which knows whether resolution failed or was not attempted.
+func resetStatusOnError(op *operatorsv1alpha1.Operator, reason string) {
+	message := fmt.Sprintf("installation has not been attempted as %s", reason)
+	resetInstallStatus(op, message)
+	setPreflightPassedStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+	setPatchesAppliedStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+	op.Status.ResolvedBundleResource = ""
+	op.Status.ResolvedBundle = nil
+	op.Status.PermissionPreview = nil
+	setUpgradeBlockedStatusConditionUnknown(&op.Status.Conditions, fmt.Sprintf("upgrade readiness has not been checked as %s", reason), op.GetGeneration())
+	op.Status.AvailableUpgrades = nil
+	setUpgradeAvailableStatusConditionUnknown(&op.Status.Conditions, fmt.Sprintf("upgrade availability has not been checked as %s", reason), op.GetGeneration())
+}
+
+// resetInstallStatus reports that the bundle of the Operator is not known to be installed, with the
+// given message on the conditions describing the installation.
+func resetInstallStatus(op *operatorsv1alpha1.Operator, message string) {
+	op.Status.InstalledBundleResource = ""
+	setInstalledStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+	setProgressingStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+	setHealthyStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
 }
 
 func mapBDStatusToInstalledCondition(existingTypedBundleDeployment *rukpakv1alpha1.BundleDeployment, op *operatorsv1alpha1.Operator) {
@@ -192,6 +443,14 @@ func mapBDStatusToInstalledCondition(existingTypedBundleDeployment *rukpakv1alph
 		return
 	}
 
+	// The conditions of a stale BundleDeployment describe a previous version of its spec,
+	// and cannot tell whether the bundle it currently references is installed.
+	if isBundleDepStale(existingTypedBundleDeployment) {
+		op.Status.InstalledBundleResource = ""
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, "bundledeployment status is out of date", op.GetGeneration())
+		return
+	}
+
 	if bundleDeploymentReady.Status != metav1.ConditionTrue {
 		op.Status.InstalledBundleResource = ""
 		setInstalledStatusConditionFailed(
@@ -211,6 +470,15 @@ func mapBDStatusToInstalledCondition(existingTypedBundleDeployment *rukpakv1alph
 			fmt.Sprintf("installed from %q", bundleDeploymentSource.Image.Ref),
 			op.GetGeneration(),
 		)
+	case rukpakv1alpha1.SourceTypeConfigMaps:
+		// the manifests rendered by operator-controller are those of the bundle image recorded on the BundleDeployment
+		image := bundleDeploymentImage(existingTypedBundleDeployment)
+		op.Status.InstalledBundleResource = image
+		setInstalledStatusConditionSuccess(
+			&op.Status.Conditions,
+			fmt.Sprintf("installed from %q", image),
+			op.GetGeneration(),
+		)
 	case rukpakv1alpha1.SourceTypeGit:
 		resource := bundleDeploymentSource.Git.Repository + "@" + bundleDeploymentSource.Git.Ref.Commit
 		op.Status.InstalledBundleResource = resource
@@ -245,36 +513,135 @@ func (r *OperatorReconciler) getBundleEntityFromSolution(solution *solver.Soluti
 	return nil, fmt.Errorf("entity for package %q not found in solution", packageName)
 }
 
-func (r *OperatorReconciler) generateExpectedBundleDeployment(o operatorsv1alpha1.Operator, bundlePath string) *unstructured.Unstructured {
+// getDependencyBundlesFromSolution returns all bundles selected in the solution that the given
+// bundle entity depends on, directly or transitively.
+func (r *OperatorReconciler) getDependencyBundlesFromSolution(solution *solver.Solution, bundleEntity *entity.BundleEntity) []*entity.BundleEntity {
+	selected := map[deppy.Identifier]*bundles_and_dependencies.BundleVariable{}
+	for _, variable := range solution.SelectedVariables() {
+		if v, ok := variable.(*bundles_and_dependencies.BundleVariable); ok {
+			selected[v.Identifier()] = v
+		}
+	}
+
+	var dependencies []*entity.BundleEntity
+	visited := map[deppy.Identifier]struct{}{bundleEntity.ID: {}}
+	queue := []deppy.Identifier{bundleEntity.ID}
+	for len(queue) > 0 {
+		var head deppy.Identifier
+		head, queue = queue[0], queue[1:]
+		variable, ok := selected[head]
+		if !ok {
+			continue
+		}
+		for _, dependency := range variable.Dependencies() {
+			if _, ok := selected[dependency.ID]; !ok {
+				continue
+			}
+			if _, ok := visited[dependency.ID]; ok {
+				continue
+			}
+			visited[dependency.ID] = struct{}{}
+			dependencies = append(dependencies, dependency)
+			queue = append(queue, dependency.ID)
+		}
+	}
+	return dependencies
+}
+
+// getClusterProvidedGVKsFromSolution returns the gvks required by the given bundle entity or its
+// dependencies that are provided by the cluster rather than by a bundle.
+func (r *OperatorReconciler) getClusterProvidedGVKsFromSolution(solution *solver.Solution, bundleEntity *entity.BundleEntity, dependencies []*entity.BundleEntity) []entity.GVK {
+	var clusterProvidedGVKs []entity.GVK
+	added := map[entity.GVK]struct{}{}
+	for _, bundle := range append([]*entity.BundleEntity{bundleEntity}, dependencies...) {
+		variable, ok := solution.SelectedVariables()[bundle.ID].(*bundles_and_dependencies.BundleVariable)
+		if !ok {
+			continue
+		}
+		for _, gvk := range variable.ClusterProvidedGVKs() {
+			if _, ok := added[gvk]; !ok {
+				added[gvk] = struct{}{}
+				clusterProvidedGVKs = append(clusterProvidedGVKs, gvk)
+			}
+		}
+	}
+	return clusterProvidedGVKs
+}
+
+// packageNames returns the names of the packages of the given bundles.
+func packageNames(bundles []*entity.BundleEntity) (sets.String, error) {
+	names := sets.NewString()
+	for _, bundle := range bundles {
+		name, err := bundle.PackageName()
+		if err != nil {
+			return nil, err
+		}
+		names.Insert(name)
+	}
+	return names, nil
+}
+
+func (r *OperatorReconciler) generateExpectedBundleDeployment(o operatorsv1alpha1.Operator, bundlePath string, manifests []corev1.ConfigMap, pullSecret string) *unstructured.Unstructured {
 	// We use unstructured here to avoid problems of serializing default values when sending patches to the apiserver.
 	// If you use a typed object, any default values from that struct get serialized into the JSON patch, which could
 	// cause unrelated fields to be patched back to the default value even though that isn't the intention. Using an
 	// unstructured ensures that the patch contains only what is specified. Using unstructured like this is basically
 	// identical to "kubectl apply -f"
+
+	// the manifests rendered by operator-controller, if any, are installed as a plain bundle, and the
+	// bundle image is installed by the registry provisioner otherwise
+	var template map[string]interface{}
+	if manifests != nil {
+		configMaps := make([]interface{}, 0, len(manifests))
+		for _, cm := range manifests {
+			configMaps = append(configMaps, map[string]interface{}{
+				"configMap": map[string]interface{}{"name": cm.GetName()},
+				"path":      bundleManifestsPath,
+			})
+		}
+		template = map[string]interface{}{
+			"provisionerClassName": "core-rukpak-io-plain",
+			"source": map[string]interface{}{
+				"type":       string(rukpakv1alpha1.SourceTypeConfigMaps),
+				"configMaps": configMaps,
+			},
+		}
+	} else {
+		image := map[string]interface{}{
+			"ref": bundlePath,
+		}
+		// the provisioner pulls the bundle image with the credentials of the pull secret, if any
+		if pullSecret != "" {
+			image["pullSecret"] = pullSecret
+		}
+		template = map[string]interface{}{
+			// TODO: Don't assume registry provisioner
+			"provisionerClassName": "core-rukpak-io-registry",
+			"source": map[string]interface{}{
+				// TODO: Don't assume image type
+				"type":  string(rukpakv1alpha1.SourceTypeImage),
+				"image": image,
+			},
+		}
+	}
+	spec := map[string]interface{}{
+		// TODO: Don't assume plain provisioner
+		"provisionerClassName": "core-rukpak-io-plain",
+		"template": map[string]interface{}{
+			"spec": template,
+		},
+	}
 	bd := &unstructured.Unstructured{Object: map[string]interface{}{
 		"apiVersion": rukpakv1alpha1.GroupVersion.String(),
 		"kind":       rukpakv1alpha1.BundleDeploymentKind,
 		"metadata": map[string]interface{}{
 			"name": o.GetName(),
 		},
-		"spec": map[string]interface{}{
-			// TODO: Don't assume plain provisioner
-			"provisionerClassName": "core-rukpak-io-plain",
-			"template": map[string]interface{}{
-				"spec": map[string]interface{}{
-					// TODO: Don't assume registry provisioner
-					"provisionerClassName": "core-rukpak-io-registry",
-					"source": map[string]interface{}{
-						// TODO: Don't assume image type
-						"type": string(rukpakv1alpha1.SourceTypeImage),
-						"image": map[string]interface{}{
-							"ref": bundlePath,
-						},
-					},
-				},
-			},
-		},
+		"spec": spec,
 	}}
+	if manifests != nil {
+		bd.SetAnnotations(map[string]string{bundleImageAnnotation: bundlePath})
+	}
 	bd.SetOwnerReferences([]metav1.OwnerReference{
 		{
 			APIVersion:         operatorsv1alpha1.GroupVersion.String(),
@@ -290,11 +657,42 @@ func (r *OperatorReconciler) generateExpectedBundleDeployment(o operatorsv1alpha
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Catalog content changes are coalesced and rate limited, as a single catalog refresh
+	// can touch a large number of Packages and BundleMetadata at once.
+	coalescer := &requestCoalescer{
+		delay:   catalogEventCoalescePeriod,
+		limiter: &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(catalogEventQPS), catalogEventBurst)},
+	}
+	catalogContentHandler := func(fn handler.MapFunc) handler.EventHandler {
+		return &coalescingEnqueueHandler{toRequests: fn, coalescer: coalescer}
+	}
+
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&operatorsv1alpha1.Operator{}).
 		Watches(source.NewKindWithCache(&catalogd.Catalog{}, mgr.GetCache()),
-			handler.EnqueueRequestsFromMapFunc(operatorRequestsForCatalog(context.TODO(), mgr.GetClient(), mgr.GetLogger()))).
+			catalogContentHandler(operatorRequestsForCatalog(context.TODO(), mgr.GetClient(), &r.dependencies, mgr.GetLogger()))).
+		Watches(source.NewKindWithCache(&catalogd.Package{}, mgr.GetCache()),
+			catalogContentHandler(operatorRequestsForPackage(context.TODO(), mgr.GetClient(), &r.dependencies, mgr.GetLogger())),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(source.NewKindWithCache(&catalogd.BundleMetadata{}, mgr.GetCache()),
+			catalogContentHandler(operatorRequestsForBundleMetadata(context.TODO(), mgr.GetClient(), &r.dependencies, mgr.GetLogger())),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(source.NewKindWithCache(&operatorsv1alpha1.InstallDefaults{}, mgr.GetCache()),
+			handler.EnqueueRequestsFromMapFunc(operatorRequestsForInstallDefaults(context.TODO(), mgr.GetClient(), mgr.GetLogger()))).
 		Owns(&rukpakv1alpha1.BundleDeployment{}).
+		// the Bundles of a BundleDeployment report why their image cannot be pulled, which does not
+		// change the status of the BundleDeployment
+		Watches(source.NewKindWithCache(&rukpakv1alpha1.Bundle{}, mgr.GetCache()),
+			handler.EnqueueRequestsFromMapFunc(operatorRequestsForWorkload),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isBundleDeploymentWorkload))).
+		// the cache of Deployments is restricted by WorkloadCacheSelectors, and only the metadata of
+		// CustomResourceDefinitions is watched, their labels being enough to map them to an Operator
+		Watches(&source.Kind{Type: &appsv1.Deployment{}},
+			handler.EnqueueRequestsFromMapFunc(operatorRequestsForWorkload),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isBundleDeploymentWorkload))).
+		Watches(&source.Kind{Type: &apiextensionsv1.CustomResourceDefinition{}},
+			handler.EnqueueRequestsFromMapFunc(operatorRequestsForWorkload),
+			builder.OnlyMetadata, builder.WithPredicates(predicate.NewPredicateFuncs(isBundleDeploymentWorkload))).
 		Complete(r)
 
 	if err != nil {
@@ -321,7 +719,7 @@ func (r *OperatorReconciler) ensureBundleDeployment(ctx context.Context, desired
 		return nil
 	}
 
-	return r.Client.Patch(ctx, desiredBundleDeployment, client.Apply, client.ForceOwnership, client.FieldOwner("operator-controller"))
+	return r.Client.Patch(ctx, desiredBundleDeployment, client.Apply, client.ForceOwnership, client.FieldOwner(bundleDeploymentFieldOwner))
 }
 
 func (r *OperatorReconciler) existingBundleDeploymentUnstructured(ctx context.Context, name string) (*unstructured.Unstructured, error) {
@@ -339,6 +737,47 @@ func (r *OperatorReconciler) existingBundleDeploymentUnstructured(ctx context.Co
 	return &unstructured.Unstructured{Object: unstrExistingBundleDeploymentObj}, nil
 }
 
+// mapBDStatusToProgressingCondition sets the progressing condition of the operator based on the
+// rollout stage of its BundleDeployment: unpacking the bundle, applying its content, or done.
+func mapBDStatusToProgressingCondition(existingTypedBundleDeployment *rukpakv1alpha1.BundleDeployment, op *operatorsv1alpha1.Operator) {
+	if isBundleDepStale(existingTypedBundleDeployment) {
+		setProgressingStatusConditionStale(
+			&op.Status.Conditions,
+			fmt.Sprintf("waiting for bundledeployment to observe generation %d, last observed generation is %d",
+				existingTypedBundleDeployment.GetGeneration(), existingTypedBundleDeployment.Status.ObservedGeneration),
+			op.GetGeneration(),
+		)
+		return
+	}
+
+	status, message := verifyBDStatus(existingTypedBundleDeployment)
+	switch status {
+	case metav1.ConditionTrue:
+		setProgressingStatusConditionSucceeded(&op.Status.Conditions, message, op.GetGeneration())
+		return
+	case metav1.ConditionFalse:
+		if apimeta.IsStatusConditionFalse(existingTypedBundleDeployment.Status.Conditions, rukpakv1alpha1.TypeHasValidBundle) {
+			setProgressingStatusConditionInvalidBundle(&op.Status.Conditions, message, op.GetGeneration())
+			return
+		}
+		setProgressingStatusConditionFailed(&op.Status.Conditions, message, op.GetGeneration())
+		return
+	}
+
+	// Neither installed nor failed yet, find out how far the rollout got.
+	hasValidBundle := apimeta.FindStatusCondition(existingTypedBundleDeployment.Status.Conditions, rukpakv1alpha1.TypeHasValidBundle)
+	switch {
+	case hasValidBundle == nil:
+		setProgressingStatusConditionUnknown(&op.Status.Conditions, message, op.GetGeneration())
+	case hasValidBundle.Status == metav1.ConditionTrue && hasValidBundle.Reason == rukpakv1alpha1.ReasonUnpackSuccessful:
+		setProgressingStatusConditionApplying(&op.Status.Conditions, "applying the bundle content", op.GetGeneration())
+	case hasValidBundle.Status == metav1.ConditionTrue:
+		setProgressingStatusConditionUnpacking(&op.Status.Conditions, hasValidBundle.Message, op.GetGeneration())
+	default:
+		setProgressingStatusConditionUnknown(&op.Status.Conditions, hasValidBundle.Message, op.GetGeneration())
+	}
+}
+
 // verifyBDStatus reads the various possibilities of status in bundle deployment and translates
 // into corresponding operator condition status and message.
 func verifyBDStatus(dep *rukpakv1alpha1.BundleDeployment) (metav1.ConditionStatus, string) {
@@ -419,6 +858,18 @@ func setInstalledStatusConditionFailed(conditions *[]metav1.Condition, message s
 	})
 }
 
+// setInstalledStatusConditionImagePullUnauthorized sets the installed status condition to false, as
+// the bundle image cannot be pulled without valid credentials.
+func setInstalledStatusConditionImagePullUnauthorized(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonImagePullUnauthorized,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
 // setInstalledStatusConditionUnknown sets the installed status condition to unknown.
 func setInstalledStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
 	apimeta.SetStatusCondition(conditions, metav1.Condition{
@@ -430,25 +881,316 @@ func setInstalledStatusConditionUnknown(conditions *[]metav1.Condition, message
 	})
 }
 
-// Generate reconcile requests for all operators affected by a catalog change
-func operatorRequestsForCatalog(ctx context.Context, c client.Reader, logger logr.Logger) handler.MapFunc {
-	return func(object client.Object) []reconcile.Request {
-		// no way of associating an operator to a catalog so create reconcile requests for everything
-		operators := operatorsv1alpha1.OperatorList{}
-		err := c.List(ctx, &operators)
-		if err != nil {
-			logger.Error(err, "unable to enqueue operators for catalog reconcile")
-			return nil
-		}
-		var requests []reconcile.Request
-		for _, op := range operators.Items {
-			requests = append(requests, reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Namespace: op.GetNamespace(),
-					Name:      op.GetName(),
-				},
-			})
-		}
-		return requests
-	}
+// setUpgradeBlockedStatusConditionBlocked sets the upgrade blocked status condition to true.
+func setUpgradeBlockedStatusConditionBlocked(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeUpgradeBlocked,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonNotUpgradeable,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setUpgradeBlockedStatusConditionAllowed sets the upgrade blocked status condition to false.
+func setUpgradeBlockedStatusConditionAllowed(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeUpgradeBlocked,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonUpgradeAllowed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setUpgradeBlockedStatusConditionForced sets the upgrade blocked status condition to false,
+// as the upgrade was forced by the user.
+func setUpgradeBlockedStatusConditionForced(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeUpgradeBlocked,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonUpgradeForced,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setUpgradeBlockedStatusConditionUnknown sets the upgrade blocked status condition to unknown.
+func setUpgradeBlockedStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeUpgradeBlocked,
+		Status:             metav1.ConditionUnknown,
+		Reason:             operatorsv1alpha1.ReasonUpgradeStatusUnknown,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setUpgradeAvailableStatusConditionAvailable sets the upgrade available status condition to true.
+func setUpgradeAvailableStatusConditionAvailable(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeUpgradeAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonNewerVersionsAvailable,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setUpgradeAvailableStatusConditionUpToDate sets the upgrade available status condition to false.
+func setUpgradeAvailableStatusConditionUpToDate(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeUpgradeAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonUpToDate,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setUpgradeAvailableStatusConditionUnknown sets the upgrade available status condition to unknown.
+func setUpgradeAvailableStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeUpgradeAvailable,
+		Status:             metav1.ConditionUnknown,
+		Reason:             operatorsv1alpha1.ReasonUpgradeAvailabilityUnknown,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionStale sets the progressing status condition to true, as the
+// bundledeployment has yet to observe its latest spec.
+func setProgressingStatusConditionStale(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonBundleDeploymentStale,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionUnpacking sets the progressing status condition to true, as the bundle is being unpacked.
+func setProgressingStatusConditionUnpacking(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonUnpacking,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionApplying sets the progressing status condition to true, as the bundle content is being applied.
+func setProgressingStatusConditionApplying(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonApplying,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionSucceeded sets the progressing status condition to false, as the rollout succeeded.
+func setProgressingStatusConditionSucceeded(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonInstallationSucceeded,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionInvalidBundle sets the progressing status condition to false, as the bundle is invalid.
+func setProgressingStatusConditionInvalidBundle(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonInvalidBundle,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionImagePullUnauthorized sets the progressing status condition to false,
+// as the bundle image cannot be pulled without valid credentials.
+func setProgressingStatusConditionImagePullUnauthorized(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonImagePullUnauthorized,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionFailed sets the progressing status condition to false, as the rollout failed.
+func setProgressingStatusConditionFailed(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonInstallationFailed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionUnknown sets the progressing status condition to unknown.
+func setProgressingStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionUnknown,
+		Reason:             operatorsv1alpha1.ReasonInstallationStatusUnknown,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionDeadlineExceeded sets the installed status condition to failed, as the progress deadline was exceeded.
+func setInstalledStatusConditionDeadlineExceeded(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonProgressDeadlineExceeded,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setProgressingStatusConditionDeadlineExceeded sets the progressing status condition to false, as the progress deadline was exceeded.
+func setProgressingStatusConditionDeadlineExceeded(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonProgressDeadlineExceeded,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setHealthyStatusConditionHealthy sets the healthy status condition to true.
+func setHealthyStatusConditionHealthy(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeHealthy,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonHealthy,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setHealthyStatusConditionUnhealthy sets the healthy status condition to false.
+func setHealthyStatusConditionUnhealthy(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeHealthy,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonUnhealthy,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setHealthyStatusConditionUnknown sets the healthy status condition to unknown.
+func setHealthyStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeHealthy,
+		Status:             metav1.ConditionUnknown,
+		Reason:             operatorsv1alpha1.ReasonHealthStatusUnknown,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setHealthyStatusConditionInvalidHealthChecks sets the healthy status condition to unknown, as
+// some of the health checks are invalid.
+func setHealthyStatusConditionInvalidHealthChecks(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeHealthy,
+		Status:             metav1.ConditionUnknown,
+		Reason:             operatorsv1alpha1.ReasonInvalidHealthChecks,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setPatchesAppliedStatusConditionApplied sets the patches applied status condition to true.
+func setPatchesAppliedStatusConditionApplied(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypePatchesApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonPatchesApplied,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setPatchesAppliedStatusConditionNotMatched sets the patches applied status condition to false, as
+// some patches match no object of the bundle.
+func setPatchesAppliedStatusConditionNotMatched(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypePatchesApplied,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonPatchesNotMatched,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setPatchesAppliedStatusConditionFailed sets the patches applied status condition to false, as a
+// patch fails to apply.
+func setPatchesAppliedStatusConditionFailed(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypePatchesApplied,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonPatchFailed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setPatchesAppliedStatusConditionUnknown sets the patches applied status condition to unknown.
+func setPatchesAppliedStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypePatchesApplied,
+		Status:             metav1.ConditionUnknown,
+		Reason:             operatorsv1alpha1.ReasonPatchStatusUnknown,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setPreflightPassedStatusConditionPassed sets the preflight passed status condition to true.
+func setPreflightPassedStatusConditionPassed(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypePreflightPassed,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonPreflightChecksPassed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setPreflightPassedStatusConditionFailed sets the preflight passed status condition to false.
+func setPreflightPassedStatusConditionFailed(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypePreflightPassed,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonPreflightChecksFailed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setPreflightPassedStatusConditionUnknown sets the preflight passed status condition to unknown.
+func setPreflightPassedStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypePreflightPassed,
+		Status:             metav1.ConditionUnknown,
+		Reason:             operatorsv1alpha1.ReasonPreflightStatusUnknown,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
 }
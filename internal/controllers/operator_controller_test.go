@@ -2,25 +2,40 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/operator-framework/deppy/pkg/deppy"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
 	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
 	"github.com/operator-framework/operator-controller/internal/conditionsets"
 	"github.com/operator-framework/operator-controller/internal/controllers"
+	"github.com/operator-framework/operator-controller/internal/preflight"
 	"github.com/operator-framework/operator-controller/internal/resolution"
 )
 
@@ -36,6 +51,9 @@ var _ = Describe("Operator Controller Test", func() {
 			Scheme:   sch,
 			Resolver: resolution.NewOperatorResolver(cl, testEntitySource),
 		}
+		// the manifests of the bundles rendered by operator-controller are stored in the rukpak namespace
+		rukpakNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: controllers.DefaultBundleManifestsNamespace}}
+		Expect(client.IgnoreAlreadyExists(cl.Create(ctx, rukpakNamespace))).To(Succeed())
 	})
 	When("the operator does not exist", func() {
 		It("returns no error", func() {
@@ -44,6 +62,212 @@ var _ = Describe("Operator Controller Test", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+	When("the operator specifies an install namespace and the namespaces to watch", func() {
+		var (
+			operator *operatorsv1alpha1.Operator
+			opKey    types.NamespacedName
+		)
+		BeforeEach(func() {
+			By("initializing cluster state")
+			opKey = types.NamespacedName{Name: fmt.Sprintf("operator-test-%s", rand.String(8))}
+			operator = &operatorsv1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+				Spec: operatorsv1alpha1.OperatorSpec{
+					PackageName:            "prometheus",
+					InstallNamespace:       "monitoring",
+					DeleteInstallNamespace: true,
+					WatchNamespaces:        []string{"monitoring"},
+				},
+			}
+			Expect(cl.Create(ctx, operator)).To(Succeed())
+
+			By("running reconcile")
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+			Expect(res).To(Equal(ctrl.Result{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+		})
+		AfterEach(func() {
+			By("letting go of the operator")
+			if err := cl.Get(ctx, opKey, operator); err == nil {
+				operator.SetFinalizers(nil)
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				Expect(client.IgnoreNotFound(cl.Delete(ctx, operator))).To(Succeed())
+			}
+			Expect(cl.Delete(ctx, &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}})).To(Succeed())
+			Expect(client.IgnoreNotFound(cl.Delete(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring"}}))).To(Succeed())
+		})
+		It("creates and labels the namespace, and installs the bundle rendered to be installed in it and watch it", func() {
+			ns := &corev1.Namespace{}
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "monitoring"}, ns)).To(Succeed())
+			Expect(ns.GetLabels()).To(HaveKeyWithValue("operators.operatorframework.io/install-namespace-owner", opKey.Name))
+			Expect(operator.Status.InstallNamespace).To(Equal("monitoring"))
+			Expect(operator.GetFinalizers()).To(ContainElement("operators.operatorframework.io/delete-install-namespace"))
+
+			bd := &rukpakv1alpha1.BundleDeployment{}
+			Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+			Expect(bd.Spec.Config.Raw).To(BeEmpty())
+			Expect(bd.Spec.Template.Spec.ProvisionerClassName).To(Equal("core-rukpak-io-plain"))
+			Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeConfigMaps))
+			Expect(bd.GetAnnotations()).To(HaveKeyWithValue("operators.operatorframework.io/bundle-image", operator.Status.ResolvedBundleResource))
+
+			objects := bundleManifests(ctx, bd)
+			var kinds []string
+			for _, obj := range objects {
+				kinds = append(kinds, obj.GetKind())
+				Expect(obj.GetNamespace()).To(BeElementOf("", "monitoring"))
+			}
+			Expect(kinds).To(Equal([]string{"ServiceAccount", "Role", "RoleBinding", "ClusterRole", "ClusterRoleBinding", "Deployment"}))
+			deployment := objects[len(objects)-1]
+			Expect(deployment.GetName()).To(Equal("prometheus-operator"))
+			Expect(deployment.GetAnnotations()).To(HaveKeyWithValue("olm.targetNamespaces", "monitoring"))
+		})
+		It("reports the bundle image as installed once the bundle deployment is installed", func() {
+			bd := &rukpakv1alpha1.BundleDeployment{}
+			Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+			bd.Status.ObservedGeneration = bd.GetGeneration()
+			apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+				Type:   rukpakv1alpha1.TypeHasValidBundle,
+				Status: metav1.ConditionTrue,
+				Reason: rukpakv1alpha1.ReasonUnpackSuccessful,
+			})
+			apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+				Type:   rukpakv1alpha1.TypeInstalled,
+				Status: metav1.ConditionTrue,
+				Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+			})
+			Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+			Expect(operator.Status.InstalledBundleResource).To(Equal(operator.Status.ResolvedBundleResource))
+			cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		})
+		It("replaces the manifests of the bundle when the namespaces to watch change", func() {
+			bd := &rukpakv1alpha1.BundleDeployment{}
+			Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+			previous := bd.Spec.Template.Spec.Source.ConfigMaps
+
+			operator.Spec.WatchNamespaces = nil
+			Expect(cl.Update(ctx, operator)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+			Expect(bd.Spec.Template.Spec.Source.ConfigMaps).NotTo(Equal(previous))
+			objects := bundleManifests(ctx, bd)
+			Expect(objects[len(objects)-1].GetAnnotations()).To(HaveKeyWithValue("olm.targetNamespaces", ""))
+			for _, source := range previous {
+				err := cl.Get(ctx, types.NamespacedName{Namespace: controllers.DefaultBundleManifestsNamespace, Name: source.ConfigMap.Name}, &corev1.ConfigMap{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+		It("deletes the namespace it created when the operator is deleted", func() {
+			Expect(cl.Delete(ctx, operator)).To(Succeed())
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+			Expect(res).To(Equal(ctrl.Result{}))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = cl.Get(ctx, types.NamespacedName{Name: "monitoring"}, &corev1.Namespace{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			err = cl.Get(ctx, opKey, operator)
+			if err == nil {
+				Expect(operator.GetFinalizers()).NotTo(ContainElement("operators.operatorframework.io/delete-install-namespace"))
+			} else {
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+		It("does not delete a namespace it did not create when the operator is deleted", func() {
+			ns := &corev1.Namespace{}
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "monitoring"}, ns)).To(Succeed())
+			ns.SetLabels(nil)
+			Expect(cl.Update(ctx, ns)).To(Succeed())
+
+			Expect(cl.Delete(ctx, operator)).To(Succeed())
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+			Expect(res).To(Equal(ctrl.Result{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "monitoring"}, ns)).To(Succeed())
+		})
+	})
+	When("the operator specifies an install namespace for a bundle whose objects the catalog does not provide", func() {
+		var (
+			operator *operatorsv1alpha1.Operator
+			opKey    types.NamespacedName
+		)
+		BeforeEach(func() {
+			reconciler.Resolver = resolution.NewOperatorResolver(cl, input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+				"operatorhub/prometheus/0.47.0": *input.NewEntity("operatorhub/prometheus/0.47.0", map[string]string{
+					"olm.bundle.path": `"quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"`,
+					"olm.channel":     `{"channelName":"beta","priority":0}`,
+					"olm.package":     `{"packageName":"prometheus","version":"0.47.0"}`,
+					"olm.gvk":         `[]`,
+				}),
+			}))
+			opKey = types.NamespacedName{Name: fmt.Sprintf("operator-test-%s", rand.String(8))}
+			operator = &operatorsv1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+				Spec: operatorsv1alpha1.OperatorSpec{
+					PackageName:      "prometheus",
+					InstallNamespace: "monitoring",
+				},
+			}
+			Expect(cl.Create(ctx, operator)).To(Succeed())
+		})
+		AfterEach(func() {
+			verifyInvariants(ctx, operator)
+			Expect(cl.Delete(ctx, operator)).To(Succeed())
+		})
+		It("does not install the bundle, as it cannot be installed in the namespace", func() {
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+			Expect(res).To(Equal(ctrl.Result{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+
+			err = cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, &rukpakv1alpha1.BundleDeployment{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationFailed))
+			Expect(cond.Message).To(Equal("the bundle cannot be installed as the operator configures it, as the catalog does not provide its objects"))
+		})
+		It("installs the bundle image without the config of the install defaults, which cannot be applied", func() {
+			By("installing the bundle in the namespace of the bundle")
+			operator.Spec.InstallNamespace = ""
+			Expect(cl.Update(ctx, operator)).To(Succeed())
+
+			By("configuring the deployments of every bundle")
+			defaults := &operatorsv1alpha1.InstallDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: operatorsv1alpha1.InstallDefaultsName},
+				Spec: operatorsv1alpha1.InstallDefaultsSpec{
+					Config: &operatorsv1alpha1.DeploymentConfig{NodeSelector: map[string]string{"kubernetes.io/os": "linux"}},
+				},
+			}
+			Expect(cl.Create(ctx, defaults)).To(Succeed())
+			defer func() {
+				Expect(cl.Delete(ctx, defaults)).To(Succeed())
+			}()
+
+			By("running reconcile")
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("checking the BD installs the bundle image")
+			bd := &rukpakv1alpha1.BundleDeployment{}
+			Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+			Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeImage))
+			Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+
+			By("checking the config is not reported as applied")
+			Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+			Expect(operator.Status.EffectiveConfig).To(BeNil())
+			Expect(cl.Delete(ctx, bd)).To(Succeed())
+		})
+	})
 	When("the operator exists", func() {
 		var (
 			operator *operatorsv1alpha1.Operator
@@ -157,6 +381,10 @@ var _ = Describe("Operator Controller Test", func() {
 					Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeImage))
 					Expect(bd.Spec.Template.Spec.Source.Image).NotTo(BeNil())
 					Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+					Expect(bd.Spec.Config.Raw).To(BeEmpty())
+				})
+				It("sets the installNamespace status field", func() {
+					Expect(operator.Status.InstallNamespace).To(Equal("prometheus-system"))
 				})
 				It("sets the resolvedBundleResource status field", func() {
 					Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
@@ -164,6 +392,29 @@ var _ = Describe("Operator Controller Test", func() {
 				It("sets the InstalledBundleResource status field", func() {
 					Expect(operator.Status.InstalledBundleResource).To(Equal(""))
 				})
+				It("sets the resolvedBundle status field", func() {
+					Expect(operator.Status.ResolvedBundle).To(Equal(&operatorsv1alpha1.ResolvedBundle{
+						BundleMetadata: operatorsv1alpha1.BundleMetadata{
+							Name:    "prometheusoperator.0.47.0",
+							Package: "prometheus",
+							Version: "0.47.0",
+							Channel: "beta",
+							Catalog: "operatorhub",
+							Image:   "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed",
+						},
+					}))
+				})
+				It("sets the observedGeneration status field", func() {
+					Expect(operator.Status.ObservedGeneration).To(Equal(operator.GetGeneration()))
+				})
+				It("reports that no upgrade is available", func() {
+					Expect(operator.Status.AvailableUpgrades).To(BeEmpty())
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeAvailable)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonUpToDate))
+					Expect(cond.Message).To(Equal("no newer version is available"))
+				})
 				It("sets the status on operator", func() {
 					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
 					Expect(cond).NotTo(BeNil())
@@ -197,6 +448,7 @@ var _ = Describe("Operator Controller Test", func() {
 						},
 						Spec: rukpakv1alpha1.BundleDeploymentSpec{
 							ProvisionerClassName: "core-rukpak-io-plain",
+							Config:               runtime.RawExtension{Raw: []byte(`{"installNamespace":"prometheus-system"}`)},
 							Template: &rukpakv1alpha1.BundleTemplate{
 								Spec: rukpakv1alpha1.BundleSpec{
 									ProvisionerClassName: "core-rukpak-io-registry",
@@ -507,6 +759,171 @@ var _ = Describe("Operator Controller Test", func() {
 
 					})
 
+					When("The BundleDeployment status is mapped to the expected Progressing condition", func() {
+						reconcileAndGetProgressing := func() *metav1.Condition {
+							By("updating the status of bundleDeployment")
+							Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+							By("running reconcile")
+							res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+							Expect(res).To(Equal(ctrl.Result{}))
+							Expect(err).NotTo(HaveOccurred())
+
+							By("fetching the updated operator after reconcile")
+							op := &operatorsv1alpha1.Operator{}
+							Expect(cl.Get(ctx, opKey, op)).To(Succeed())
+							return apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeProgressing)
+						}
+
+						It("reports the bundle is being unpacked", func() {
+							apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+								Type:    rukpakv1alpha1.TypeHasValidBundle,
+								Status:  metav1.ConditionTrue,
+								Message: "Waiting for the bundle to be unpacked",
+								Reason:  rukpakv1alpha1.ReasonUnpackPending,
+							})
+
+							cond := reconcileAndGetProgressing()
+							Expect(cond).NotTo(BeNil())
+							Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+							Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonUnpacking))
+							Expect(cond.Message).To(Equal("Waiting for the bundle to be unpacked"))
+						})
+
+						It("reports the bundle content is being applied", func() {
+							apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+								Type:    rukpakv1alpha1.TypeHasValidBundle,
+								Status:  metav1.ConditionTrue,
+								Message: "Successfully unpacked the bundle",
+								Reason:  rukpakv1alpha1.ReasonUnpackSuccessful,
+							})
+
+							cond := reconcileAndGetProgressing()
+							Expect(cond).NotTo(BeNil())
+							Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+							Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonApplying))
+							Expect(cond.Message).To(Equal("applying the bundle content"))
+						})
+
+						It("reports the bundle is invalid", func() {
+							apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+								Type:    rukpakv1alpha1.TypeHasValidBundle,
+								Status:  metav1.ConditionFalse,
+								Message: "Failed to unpack the bundle",
+								Reason:  rukpakv1alpha1.ReasonUnpackFailed,
+							})
+
+							cond := reconcileAndGetProgressing()
+							Expect(cond).NotTo(BeNil())
+							Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+							Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInvalidBundle))
+							Expect(cond.Message).To(Equal("Failed to unpack the bundle"))
+						})
+
+						When("the image of the bundle is being pulled", func() {
+							var bundle *rukpakv1alpha1.Bundle
+							BeforeEach(func() {
+								apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+									Type:    rukpakv1alpha1.TypeHasValidBundle,
+									Status:  metav1.ConditionTrue,
+									Message: fmt.Sprintf("Waiting for the %s-1234 Bundle to be unpacked", opKey.Name),
+									Reason:  rukpakv1alpha1.ReasonUnpackPending,
+								})
+								bundle = &rukpakv1alpha1.Bundle{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: fmt.Sprintf("%s-1234", opKey.Name),
+										Labels: map[string]string{
+											"core.rukpak.io/owner-kind": rukpakv1alpha1.BundleDeploymentKind,
+											"core.rukpak.io/owner-name": opKey.Name,
+										},
+									},
+									Spec: bd.Spec.Template.Spec,
+								}
+							})
+							AfterEach(func() {
+								Expect(cl.Delete(ctx, bundle)).To(Succeed())
+							})
+
+							It("reports the bundle image cannot be pulled without valid credentials", func() {
+								message := `Back-off pulling image "quay.io/operatorhubio/prometheus": unauthorized: authentication required`
+								apimeta.SetStatusCondition(&bundle.Status.Conditions, metav1.Condition{
+									Type:    rukpakv1alpha1.TypeUnpacked,
+									Status:  metav1.ConditionFalse,
+									Message: message,
+									Reason:  rukpakv1alpha1.ReasonUnpackPending,
+								})
+								Expect(cl.Create(ctx, bundle)).To(Succeed())
+
+								cond := reconcileAndGetProgressing()
+								Expect(cond).NotTo(BeNil())
+								Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+								Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonImagePullUnauthorized))
+								Expect(cond.Message).To(Equal(message))
+
+								op := &operatorsv1alpha1.Operator{}
+								Expect(cl.Get(ctx, opKey, op)).To(Succeed())
+								cond = apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+								Expect(cond).NotTo(BeNil())
+								Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+								Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonImagePullUnauthorized))
+								Expect(cond.Message).To(Equal(message))
+							})
+
+							It("keeps waiting for an image that fails to be pulled for another reason", func() {
+								apimeta.SetStatusCondition(&bundle.Status.Conditions, metav1.Condition{
+									Type:    rukpakv1alpha1.TypeUnpacked,
+									Status:  metav1.ConditionFalse,
+									Message: `Back-off pulling image "quay.io/operatorhubio/prometheus": i/o timeout`,
+									Reason:  rukpakv1alpha1.ReasonUnpackPending,
+								})
+								Expect(cl.Create(ctx, bundle)).To(Succeed())
+
+								cond := reconcileAndGetProgressing()
+								Expect(cond).NotTo(BeNil())
+								Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+								Expect(cond.Reason).NotTo(Equal(operatorsv1alpha1.ReasonImagePullUnauthorized))
+							})
+						})
+
+						It("reports the rollout is complete", func() {
+							apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+								Type:    rukpakv1alpha1.TypeInstalled,
+								Status:  metav1.ConditionTrue,
+								Message: "install was successful",
+								Reason:  rukpakv1alpha1.ReasonInstallationSucceeded,
+							})
+
+							cond := reconcileAndGetProgressing()
+							Expect(cond).NotTo(BeNil())
+							Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+							Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationSucceeded))
+						})
+
+						It("does not report a stale bundleDeployment as installed", func() {
+							apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+								Type:    rukpakv1alpha1.TypeInstalled,
+								Status:  metav1.ConditionTrue,
+								Message: "install was successful",
+								Reason:  rukpakv1alpha1.ReasonInstallationSucceeded,
+							})
+							bd.Status.ObservedGeneration = bd.GetGeneration() - 1
+
+							cond := reconcileAndGetProgressing()
+							Expect(cond).NotTo(BeNil())
+							Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+							Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonBundleDeploymentStale))
+
+							op := &operatorsv1alpha1.Operator{}
+							Expect(cl.Get(ctx, opKey, op)).To(Succeed())
+							Expect(op.Status.InstalledBundleResource).To(Equal(""))
+							cond = apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+							Expect(cond).NotTo(BeNil())
+							Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+							Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationStatusUnknown))
+							Expect(cond.Message).To(Equal("bundledeployment status is out of date"))
+						})
+					})
+
 				})
 
 				AfterEach(func() {
@@ -574,39 +991,1543 @@ var _ = Describe("Operator Controller Test", func() {
 					Expect(cond.Message).To(Equal("bundledeployment status is unknown"))
 				})
 			})
+			When("the installed bundle is not ready to be upgraded", func() {
+				var (
+					bd                *rukpakv1alpha1.BundleDeployment
+					operatorCondition *unstructured.Unstructured
+				)
+				const installedImage = "quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"
+				BeforeEach(func() {
+					By("creating a BD that installed an older bundle")
+					bd = &rukpakv1alpha1.BundleDeployment{
+						ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+						Spec: rukpakv1alpha1.BundleDeploymentSpec{
+							ProvisionerClassName: "core-rukpak-io-plain",
+							Config:               runtime.RawExtension{Raw: []byte(`{"installNamespace":"prometheus-system"}`)},
+							Template: &rukpakv1alpha1.BundleTemplate{
+								Spec: rukpakv1alpha1.BundleSpec{
+									ProvisionerClassName: "core-rukpak-io-registry",
+									Source: rukpakv1alpha1.BundleSource{
+										Type: rukpakv1alpha1.SourceTypeImage,
+										Image: &rukpakv1alpha1.ImageSource{
+											Ref: installedImage,
+										},
+									},
+								},
+							},
+						},
+					}
+					Expect(cl.Create(ctx, bd)).To(Succeed())
+					bd.Status.ObservedGeneration = bd.GetGeneration()
+					apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+						Type:   rukpakv1alpha1.TypeInstalled,
+						Status: metav1.ConditionTrue,
+						Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+					})
+					Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+					By("creating an OperatorCondition that reports the operator is not upgradeable")
+					operatorCondition = &unstructured.Unstructured{}
+					operatorCondition.SetAPIVersion("operators.coreos.com/v2")
+					operatorCondition.SetKind("OperatorCondition")
+					operatorCondition.SetNamespace("default")
+					operatorCondition.SetName(opKey.Name)
+					operatorCondition.SetLabels(map[string]string{"core.rukpak.io/owner-name": opKey.Name})
+					Expect(unstructured.SetNestedSlice(operatorCondition.Object, []interface{}{
+						map[string]interface{}{
+							"type":               "Upgradeable",
+							"status":             "False",
+							"reason":             "MigrationInProgress",
+							"message":            "data migration is in progress",
+							"lastTransitionTime": "2023-01-01T00:00:00Z",
+						},
+					}, "spec", "conditions")).To(Succeed())
+					Expect(cl.Create(ctx, operatorCondition)).To(Succeed())
+				})
+				AfterEach(func() {
+					Expect(cl.Delete(ctx, operatorCondition)).To(Succeed())
+					Expect(cl.Delete(ctx, bd)).To(Succeed())
+				})
+				It("holds the operator on the installed bundle", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the BD still references the installed bundle")
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(installedImage))
+					Expect(operator.Status.ResolvedBundleResource).To(Equal(installedImage))
+					Expect(operator.Status.ResolvedBundle).NotTo(BeNil())
+					Expect(operator.Status.ResolvedBundle.Version).To(Equal("0.37.0"))
+					Expect(operator.Status.ResolvedBundle.Image).To(Equal(installedImage))
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeBlocked)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonNotUpgradeable))
+					Expect(cond.Message).To(ContainSubstring("data migration is in progress"))
+					cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeAvailable)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+					Expect(operator.Status.AvailableUpgrades).To(Equal([]operatorsv1alpha1.AvailableUpgrade{
+						{Version: "0.47.0", Channel: "beta"},
+					}))
+				})
+				It("leaves the installed bundle untouched when the catalogs no longer provide it", func() {
+					By("installing a bundle that no catalog provides")
+					const unknownImage = "quay.io/operatorhubio/prometheus@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+					bd.Spec.Template.Spec.Source.Image.Ref = unknownImage
+					Expect(cl.Update(ctx, bd)).To(Succeed())
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					installedSpec := bd.Spec.DeepCopy()
+
+					By("running reconcile")
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).To(MatchError(ContainSubstring("not found")))
+
+					By("checking the BD is unchanged")
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(bd.Spec).To(Equal(*installedSpec))
+
+					By("checking the upgrade is reported as blocked")
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeBlocked)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonNotUpgradeable))
+					Expect(cond.Message).To(ContainSubstring("the installed bundle is left as it is"))
+				})
+				It("upgrades anyway when the upgrade is forced", func() {
+					By("forcing the upgrade")
+					operator.Spec.ForceUpgradeVersion = "0.47.0"
+					Expect(cl.Update(ctx, operator)).To(Succeed())
+
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the BD references the newly resolved bundle")
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+					Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeBlocked)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonUpgradeForced))
+
+					By("checking the permissions of the upgrade are previewed")
+					preview := operator.Status.PermissionPreview
+					Expect(preview).NotTo(BeNil())
+					Expect(preview.BundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+					Expect(preview.InstalledBundleResource).To(Equal(installedImage))
+					Expect(preview.ClusterRules).To(HaveLen(3))
+					Expect(preview.NamespaceRules).To(BeEmpty())
+					Expect(preview.AddedPermissions).To(Equal([]string{"get secrets"}))
+					Expect(preview.RemovedPermissions).To(Equal([]string{"list configmaps"}))
+					Expect(preview.Summary).To(Equal("the bundle grants 3 rules cluster-wide; compared to the installed bundle, permissions added: 1, removed: 1"))
+				})
+				It("still holds the operator when another version is forced", func() {
+					By("forcing the upgrade to another version")
+					operator.Spec.ForceUpgradeVersion = "0.38.0"
+					Expect(cl.Update(ctx, operator)).To(Succeed())
+
+					By("running reconcile")
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+
+					By("checking the BD still references the installed bundle")
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(installedImage))
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeBlocked)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonNotUpgradeable))
+				})
+				It("leaves the install status alone when the upgrade readiness cannot be read", func() {
+					By("running reconcile")
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+
+					By("reporting the installed bundle")
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					bd.Status.ObservedGeneration = bd.GetGeneration()
+					Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					operator.Status.InstalledBundleResource = installedImage
+					apimeta.SetStatusCondition(&operator.Status.Conditions, metav1.Condition{
+						Type:   operatorsv1alpha1.TypeInstalled,
+						Status: metav1.ConditionTrue,
+						Reason: operatorsv1alpha1.ReasonSuccess,
+					})
+					Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+					By("failing to list the OperatorConditions")
+					reconciler.Client = &failingListClient{Client: cl}
+					_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).To(MatchError("not today"))
+
+					By("checking the install status is unchanged")
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					Expect(operator.Status.InstalledBundleResource).To(Equal(installedImage))
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+					cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeBlocked)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+					Expect(cond.Message).To(Equal("not today"))
+				})
+			})
 		})
-		When("the selected bundle's image ref cannot be parsed", func() {
-			const pkgName = "badimage"
+		When("the operator specifies a progress deadline", func() {
 			BeforeEach(func() {
 				By("initializing cluster state")
 				operator = &operatorsv1alpha1.Operator{
 					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
-					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: pkgName},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:             "prometheus",
+						ProgressDeadlineSeconds: pointer.Int32(600),
+					},
 				}
 				err := cl.Create(ctx, operator)
 				Expect(err).NotTo(HaveOccurred())
 			})
-			It("sets resolution failure status and returns an error", func() {
+			AfterEach(func() {
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(client.IgnoreNotFound(cl.Delete(ctx, bd))).To(Succeed())
+			})
+			It("starts an install attempt and requeues when the deadline is due", func() {
 				By("running reconcile")
 				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
-				Expect(res).To(Equal(ctrl.Result{}))
-				Expect(err).To(MatchError(ContainSubstring(`error determining bundle path for entity`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(res.RequeueAfter).To(BeNumerically("<=", 600*time.Second))
 
 				By("fetching updated operator after reconcile")
 				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
 
-				By("Checking the status fields")
-				Expect(operator.Status.ResolvedBundleResource).To(Equal(""))
-				Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+				By("checking the install attempt")
+				Expect(operator.Status.InstallAttempt).NotTo(BeNil())
+				Expect(operator.Status.InstallAttempt.BundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+				Expect(operator.Status.InstallAttempt.StartTime.IsZero()).To(BeFalse())
+				Expect(operator.Status.InstallAttempt.CompletionTime).To(BeNil())
+				Expect(operator.Status.InstallAttempt.FailureTime).To(BeNil())
+			})
+			It("fails the installation for good once the deadline is exceeded", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
 
-				By("checking the expected conditions")
-				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
-				Expect(cond).NotTo(BeNil())
-				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
-				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
-				Expect(cond.Message).To(ContainSubstring(`error determining bundle path for entity`))
-				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
-				Expect(cond).NotTo(BeNil())
+				By("moving the start of the install attempt past the deadline")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				operator.Status.InstallAttempt.StartTime = metav1.NewTime(time.Now().Add(-time.Hour))
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstallAttempt.FailureTime).NotTo(BeNil())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonProgressDeadlineExceeded))
+				Expect(cond.Message).To(Equal("installation of \"quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed\" did not complete within the progress deadline"))
+
+				By("reporting the bundleDeployment as installed afterwards")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				bd.Status.ObservedGeneration = bd.GetGeneration()
+				apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+					Type:   rukpakv1alpha1.TypeInstalled,
+					Status: metav1.ConditionTrue,
+					Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+				})
+				Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the installation is still failed")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonProgressDeadlineExceeded))
+			})
+			It("starts a new install attempt once the spec changes, even if the next reconcile stops early", func() {
+				By("failing the install attempt")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				operator.Status.InstallAttempt.StartTime = metav1.NewTime(time.Now().Add(-time.Hour))
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstallAttempt.FailureTime).NotTo(BeNil())
+
+				By("changing the spec while resolution fails")
+				operator.Spec.ProgressDeadlineSeconds = pointer.Int32(900)
+				// the API server bumps the generation itself, which not every test client does
+				operator.Generation++
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				resolver := reconciler.Resolver
+				reconciler.Resolver = resolution.NewOperatorResolver(cl, input.NewCacheQuerier(map[deppy.Identifier]input.Entity{}))
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).To(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.ObservedGeneration).To(Equal(operator.GetGeneration()))
+
+				By("running reconcile once resolution succeeds again")
+				reconciler.Resolver = resolver
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking a new install attempt started")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstallAttempt.Generation).To(Equal(operator.GetGeneration()))
+				Expect(operator.Status.InstallAttempt.FailureTime).To(BeNil())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Reason).NotTo(Equal(operatorsv1alpha1.ReasonProgressDeadlineExceeded))
+			})
+			It("fails an installation held back by the preflight checks once the deadline is exceeded", func() {
+				reconciler.PreflightChecks = []preflight.Check{failingCheck{}}
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("moving the start of the install attempt past the deadline")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstallAttempt).NotTo(BeNil())
+				operator.Status.InstallAttempt.StartTime = metav1.NewTime(time.Now().Add(-time.Hour))
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(time.Minute))
+
+				By("checking the installation failed")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstallAttempt.FailureTime).NotTo(BeNil())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonProgressDeadlineExceeded))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			})
+			It("records the completion of the install attempt", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reporting the bundleDeployment as installed")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				bd.Status.ObservedGeneration = bd.GetGeneration()
+				apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+					Type:   rukpakv1alpha1.TypeInstalled,
+					Status: metav1.ConditionTrue,
+					Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+				})
+				Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstallAttempt.CompletionTime).NotTo(BeNil())
+				Expect(operator.Status.InstallAttempt.FailureTime).To(BeNil())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			})
+		})
+		When("the operator opts in to rollbacks", func() {
+			const (
+				goodImage = "quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"
+				badImage  = "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"
+			)
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:             "prometheus",
+						RollbackOnFailure:       true,
+						ProgressDeadlineSeconds: pointer.Int32(60),
+					},
+				}
+				err := cl.Create(ctx, operator)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			AfterEach(func() {
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(cl.Delete(ctx, bd)).To(Succeed())
+			})
+			expireInstallAttempt := func() {
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.InstallAttempt).NotTo(BeNil())
+				operator.Status.InstallAttempt.StartTime = metav1.NewTime(time.Now().Add(-time.Hour))
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+			}
+			setBDInstalled := func(status metav1.ConditionStatus, reason, message string) {
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				bd.Status.ObservedGeneration = bd.GetGeneration()
+				apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+					Type:    rukpakv1alpha1.TypeInstalled,
+					Status:  status,
+					Reason:  reason,
+					Message: message,
+				})
+				Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+			}
+			It("records the last known-good bundle", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reporting the bundleDeployment as installed")
+				setBDInstalled(metav1.ConditionTrue, rukpakv1alpha1.ReasonInstallationSucceeded, "")
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.LastKnownGoodBundle).NotTo(BeNil())
+				Expect(operator.Status.LastKnownGoodBundle.Image).To(Equal(badImage))
+				Expect(operator.Status.LastKnownGoodBundle.Version).To(Equal("0.47.0"))
+				Expect(operator.Status.FailedBundle).To(BeNil())
+			})
+			It("rolls back to the last known-good bundle when the upgrade fails", func() {
+				By("recording an earlier bundle as known-good")
+				operator.Status.LastKnownGoodBundle = &operatorsv1alpha1.BundleMetadata{
+					Package: "prometheus",
+					Version: "0.37.0",
+					Image:   goodImage,
+				}
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reporting the bundleDeployment installation as failed")
+				setBDInstalled(metav1.ConditionFalse, rukpakv1alpha1.ReasonInstallFailed, "failed to install")
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Requeue).To(BeFalse())
+
+				By("checking the failure is not rolled back from before the progress deadline")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.FailedBundle).To(BeNil())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(badImage))
+
+				By("running reconcile past the progress deadline")
+				expireInstallAttempt()
+				res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Requeue).To(BeTrue())
+
+				By("checking the failed bundle is recorded")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.FailedBundle).NotTo(BeNil())
+				Expect(operator.Status.FailedBundle.Image).To(Equal(badImage))
+				Expect(operator.Status.FailedBundle.Generation).To(Equal(operator.GetGeneration()))
+				Expect(operator.Status.FailedBundle.Message).To(ContainSubstring("did not complete within the progress deadline"))
+				Expect(operator.Status.InstallHistory).To(HaveLen(1))
+				Expect(operator.Status.InstallHistory[0].Image).To(Equal(badImage))
+				Expect(operator.Status.InstallHistory[0].Outcome).To(Equal(operatorsv1alpha1.InstallOutcomeFailed))
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the operator was rolled back")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(goodImage))
+				Expect(operator.Status.FailedBundle).NotTo(BeNil())
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(goodImage))
+			})
+			It("stays on the last known-good bundle when it fails to install too", func() {
+				By("adding a version between the known-good bundle and the latest one")
+				entities := map[deppy.Identifier]input.Entity{}
+				for _, bundle := range []struct{ version, image string }{
+					{"0.37.0", goodImage}, {"0.40.0", "quay.io/operatorhubio/prometheus:v0.40.0"}, {"0.47.0", badImage},
+				} {
+					id := deppy.IdentifierFromString("operatorhub/prometheus/" + bundle.version)
+					entities[id] = *input.NewEntity(id, map[string]string{
+						"olm.bundle.path": fmt.Sprintf("%q", bundle.image),
+						"olm.channel":     `{"channelName":"beta","priority":0}`,
+						"olm.package":     fmt.Sprintf(`{"packageName":"prometheus","version":%q}`, bundle.version),
+						"olm.gvk":         `[]`,
+					})
+				}
+				reconciler.Resolver = resolution.NewOperatorResolver(cl, input.NewCacheQuerier(entities))
+
+				By("recording an earlier bundle as known-good")
+				operator.Status.LastKnownGoodBundle = &operatorsv1alpha1.BundleMetadata{
+					Package: "prometheus",
+					Version: "0.37.0",
+					Image:   goodImage,
+				}
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+				By("failing to install the latest bundle")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				setBDInstalled(metav1.ConditionFalse, rukpakv1alpha1.ReasonInstallFailed, "failed to install")
+				expireInstallAttempt()
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Requeue).To(BeTrue())
+
+				By("rolling back to the known-good bundle rather than the next-highest version")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(goodImage))
+
+				By("failing to install the known-good bundle too")
+				setBDInstalled(metav1.ConditionFalse, rukpakv1alpha1.ReasonInstallFailed, "failed to install again")
+				expireInstallAttempt()
+				res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Requeue).To(BeFalse())
+
+				By("checking the operator stays on the known-good bundle")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(goodImage))
+				Expect(operator.Status.FailedBundle).NotTo(BeNil())
+				Expect(operator.Status.FailedBundle.Image).To(Equal(badImage))
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(goodImage))
+			})
+			It("rolls back from a bundle that does not become healthy within the progress deadline", func() {
+				By("recording an earlier bundle as known-good")
+				operator.Status.LastKnownGoodBundle = &operatorsv1alpha1.BundleMetadata{
+					Package: "prometheus",
+					Version: "0.37.0",
+					Image:   goodImage,
+				}
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+				By("installing the latest bundle, whose deployment has no ready pods")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				setBDInstalled(metav1.ConditionTrue, rukpakv1alpha1.ReasonInstallationSucceeded, "")
+				deployment := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "prometheus-operator",
+						Namespace: "default",
+						Labels: map[string]string{
+							"core.rukpak.io/owner-kind": rukpakv1alpha1.BundleDeploymentKind,
+							"core.rukpak.io/owner-name": opKey.Name,
+						},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Replicas: pointer.Int32(1),
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus-operator"}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "prometheus-operator"}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "operator", Image: "quay.io/prometheus-operator/prometheus-operator"}},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, deployment)).To(Succeed())
+				defer func() {
+					Expect(cl.Delete(ctx, deployment)).To(Succeed())
+				}()
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Requeue).To(BeFalse())
+				Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+
+				By("checking the unhealthy bundle is neither known-good nor rolled back from yet")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(apimeta.IsStatusConditionFalse(operator.Status.Conditions, operatorsv1alpha1.TypeHealthy)).To(BeTrue())
+				Expect(operator.Status.LastKnownGoodBundle.Image).To(Equal(goodImage))
+				Expect(operator.Status.FailedBundle).To(BeNil())
+
+				By("running reconcile past the progress deadline")
+				expireInstallAttempt()
+				res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Requeue).To(BeTrue())
+
+				By("checking the failed bundle is recorded")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.FailedBundle).NotTo(BeNil())
+				Expect(operator.Status.FailedBundle.Image).To(Equal(badImage))
+				Expect(operator.Status.FailedBundle.Message).To(ContainSubstring("did not become healthy within the progress deadline"))
+			})
+			It("upgrades from the known-good bundle once the catalog provides a newer bundle", func() {
+				entities := map[deppy.Identifier]input.Entity{}
+				addBundle := func(version, image string) {
+					id := deppy.IdentifierFromString("operatorhub/prometheus/" + version)
+					entities[id] = *input.NewEntity(id, map[string]string{
+						"olm.bundle.path": fmt.Sprintf("%q", image),
+						"olm.channel":     `{"channelName":"beta","priority":0}`,
+						"olm.package":     fmt.Sprintf(`{"packageName":"prometheus","version":%q}`, version),
+						"olm.gvk":         `[]`,
+					})
+					reconciler.Resolver = resolution.NewOperatorResolver(cl, input.NewCacheQuerier(entities))
+				}
+				addBundle("0.37.0", goodImage)
+				addBundle("0.47.0", badImage)
+
+				By("recording an earlier bundle as known-good")
+				operator.Status.LastKnownGoodBundle = &operatorsv1alpha1.BundleMetadata{
+					Package: "prometheus",
+					Version: "0.37.0",
+					Image:   goodImage,
+				}
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+				By("rolling back from the latest bundle")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				setBDInstalled(metav1.ConditionFalse, rukpakv1alpha1.ReasonInstallFailed, "failed to install")
+				expireInstallAttempt()
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(goodImage))
+
+				By("publishing a fixed bundle in the catalog")
+				const fixedImage = "quay.io/operatorhubio/prometheus:v0.47.1"
+				addBundle("0.47.1", fixedImage)
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the operator upgrades to the fixed bundle")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(fixedImage))
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(fixedImage))
+			})
+		})
+		When("the operator installs bundles over time", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				err := cl.Create(ctx, operator)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			AfterEach(func() {
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(cl.Delete(ctx, bd)).To(Succeed())
+			})
+			installBundle := func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reporting the bundleDeployment as installed")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				bd.Status.ObservedGeneration = bd.GetGeneration()
+				apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+					Type:   rukpakv1alpha1.TypeInstalled,
+					Status: metav1.ConditionTrue,
+					Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+				})
+				Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+			}
+			It("records each installed bundle and when it was replaced", func() {
+				installBundle()
+				Expect(operator.Status.InstallHistory).To(HaveLen(1))
+				Expect(operator.Status.InstallHistory[0].Version).To(Equal("0.47.0"))
+				Expect(operator.Status.InstallHistory[0].Catalog).To(Equal("operatorhub"))
+				Expect(operator.Status.InstallHistory[0].Outcome).To(Equal(operatorsv1alpha1.InstallOutcomeSucceeded))
+				Expect(operator.Status.InstallHistory[0].ReplaceTime).To(BeNil())
+
+				By("changing the version of the operator")
+				operator.Spec.Version = "0.37.0"
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				installBundle()
+
+				Expect(operator.Status.InstallHistory).To(HaveLen(2))
+				Expect(operator.Status.InstallHistory[0].Version).To(Equal("0.47.0"))
+				Expect(operator.Status.InstallHistory[0].ReplaceTime).NotTo(BeNil())
+				Expect(operator.Status.InstallHistory[1].Version).To(Equal("0.37.0"))
+				Expect(operator.Status.InstallHistory[1].Image).To(Equal("quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"))
+				Expect(operator.Status.InstallHistory[1].Outcome).To(Equal(operatorsv1alpha1.InstallOutcomeSucceeded))
+				Expect(operator.Status.InstallHistory[1].ReplaceTime).To(BeNil())
+			})
+			It("keeps only the most recent revisions", func() {
+				By("recording a full install history")
+				for i := 0; i < 10; i++ {
+					operator.Status.InstallHistory = append(operator.Status.InstallHistory, operatorsv1alpha1.InstallRevision{
+						BundleMetadata: operatorsv1alpha1.BundleMetadata{
+							Package: "prometheus",
+							Version: fmt.Sprintf("0.%d.0", i),
+							Image:   fmt.Sprintf("quay.io/operatorhubio/prometheus:v0.%d.0", i),
+						},
+						InstallTime: metav1.Now(),
+						Outcome:     operatorsv1alpha1.InstallOutcomeFailed,
+					})
+				}
+				Expect(cl.Status().Update(ctx, operator)).To(Succeed())
+
+				installBundle()
+				Expect(operator.Status.InstallHistory).To(HaveLen(10))
+				Expect(operator.Status.InstallHistory[0].Version).To(Equal("0.1.0"))
+				Expect(operator.Status.InstallHistory[9].Version).To(Equal("0.47.0"))
+			})
+		})
+		When("the operator installs workloads", func() {
+			var deployment *appsv1.Deployment
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				err := cl.Create(ctx, operator)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("creating a deployment on behalf of the bundleDeployment")
+				deployment = &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "prometheus-operator",
+						Namespace: "default",
+						Labels: map[string]string{
+							"core.rukpak.io/owner-kind": rukpakv1alpha1.BundleDeploymentKind,
+							"core.rukpak.io/owner-name": opKey.Name,
+						},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Replicas: pointer.Int32(1),
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus-operator"}},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "prometheus-operator"}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "operator", Image: "quay.io/prometheus-operator/prometheus-operator"}},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, deployment)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(cl.Delete(ctx, deployment)).To(Succeed())
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(cl.Delete(ctx, bd)).To(Succeed())
+			})
+			setDeploymentStatus := func(available corev1.ConditionStatus, readyReplicas int32) {
+				reason, message := "MinimumReplicasAvailable", "Deployment has minimum availability."
+				if available != corev1.ConditionTrue {
+					reason, message = "MinimumReplicasUnavailable", "Deployment does not have minimum availability."
+				}
+				Expect(cl.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+				deployment.Status = appsv1.DeploymentStatus{
+					ObservedGeneration: deployment.GetGeneration(),
+					Replicas:           1,
+					ReadyReplicas:      readyReplicas,
+					Conditions: []appsv1.DeploymentCondition{{
+						Type:    appsv1.DeploymentAvailable,
+						Status:  available,
+						Reason:  reason,
+						Message: message,
+					}},
+				}
+				Expect(cl.Status().Update(ctx, deployment)).To(Succeed())
+			}
+			It("only checks the health of the workloads once the bundle is installed", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonHealthStatusUnknown))
+				Expect(cond.Message).To(Equal("health is checked once the bundle is installed"))
+			})
+			It("reports the health of the installed workloads", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reporting the bundleDeployment as installed")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				bd.Status.ObservedGeneration = bd.GetGeneration()
+				apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+					Type:   rukpakv1alpha1.TypeInstalled,
+					Status: metav1.ConditionTrue,
+					Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+				})
+				Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+				By("reporting the deployment as available without ready pods")
+				setDeploymentStatus(corev1.ConditionTrue, 0)
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the operator is unhealthy")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonUnhealthy))
+				Expect(cond.Message).To(Equal("deployment default/prometheus-operator has 0 of 1 pods ready"))
+
+				By("reporting the deployment as available with ready pods")
+				setDeploymentStatus(corev1.ConditionTrue, 1)
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the operator is healthy")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonHealthy))
+
+				By("reporting the deployment as unavailable")
+				setDeploymentStatus(corev1.ConditionFalse, 0)
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the operator is unhealthy")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Message).To(Equal("deployment default/prometheus-operator is not available: Deployment does not have minimum availability."))
+			})
+			It("folds the custom health checks of the operator into its health", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}))
+				defer server.Close()
+
+				By("declaring a health check on the operator")
+				operator.Spec.HealthChecks = []operatorsv1alpha1.HealthCheck{{
+					Name:    "healthz",
+					HTTPGet: &operatorsv1alpha1.HTTPGetHealthCheck{URL: server.URL + "/healthz"},
+				}}
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reporting the bundleDeployment as installed and the deployment as available")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				bd.Status.ObservedGeneration = bd.GetGeneration()
+				apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+					Type:   rukpakv1alpha1.TypeInstalled,
+					Status: metav1.ConditionTrue,
+					Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+				})
+				Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+				setDeploymentStatus(corev1.ConditionTrue, 1)
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(time.Minute))
+
+				By("checking the operator is unhealthy")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonUnhealthy))
+				Expect(cond.Message).To(Equal(fmt.Sprintf("health check \"healthz\" failed: GET %s/healthz responded with 503 Service Unavailable", server.URL)))
+			})
+			It("reports the invalid health checks declared by the bundle", func() {
+				By("declaring an http health check in the bundle")
+				reconciler.Resolver = resolution.NewOperatorResolver(cl, input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+					"operatorhub/prometheus/0.47.0": *input.NewEntity("operatorhub/prometheus/0.47.0", map[string]string{
+						"olm.bundle.path": `"quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"`,
+						"olm.channel":     `{"channelName":"beta","priority":0}`,
+						"olm.package":     `{"packageName":"prometheus","version":"0.47.0"}`,
+						"olm.gvk":         `[]`,
+						"olm.healthcheck": `[{"name":"metadata","httpGet":{"url":"http://169.254.169.254/latest/meta-data"}}]`,
+					}),
+				}))
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the invalid health check is reported")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInvalidHealthChecks))
+				Expect(cond.Message).To(ContainSubstring(`health check "metadata" declared by the bundle must not specify httpGet`))
+			})
+		})
+		When("the operator configures the deployments of the bundle", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						Config: &operatorsv1alpha1.DeploymentConfig{
+							Env:          []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+							NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+							Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			AfterEach(func() {
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(cl.Delete(ctx, bd)).To(Succeed())
+			})
+			It("applies the config to the rendered deployments and reports it as effective", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the deployment installed by the BD")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Config.Raw).To(BeEmpty())
+				Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeConfigMaps))
+				pod := renderedDeployment(ctx, bd).Spec.Template.Spec
+				Expect(pod.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/infra": ""}))
+				Expect(pod.Tolerations).To(Equal([]corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}))
+				Expect(pod.Containers).To(HaveLen(1))
+				Expect(pod.Containers[0].Env).To(Equal([]corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}}))
+
+				By("checking the effective config")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.EffectiveConfig).To(Equal(operator.Spec.Config))
+			})
+			It("switches the bundleDeployment between the bundle image and the rendered manifests in place", func() {
+				image := "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"
+				config := operator.Spec.Config
+
+				By("installing the bundle image as is")
+				operator.Spec.Config = nil
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeImage))
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(image))
+				uid := bd.GetUID()
+
+				By("configuring the deployments of the bundle")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				operator.Spec.Config = config
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the same bundleDeployment installs the rendered manifests")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.GetUID()).To(Equal(uid))
+				Expect(bd.GetAnnotations()).To(HaveKeyWithValue("operators.operatorframework.io/bundle-image", image))
+				Expect(bd.Spec.ProvisionerClassName).To(Equal("core-rukpak-io-plain"))
+				Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeConfigMaps))
+				Expect(bd.Spec.Template.Spec.Source.Image).To(BeNil())
+				Expect(bd.Spec.Template.Spec.Source.ConfigMaps).NotTo(BeEmpty())
+				configMaps := bd.Spec.Template.Spec.Source.ConfigMaps
+
+				By("checking the objects keep the names the registry provisioner gives them")
+				var names []string
+				for _, obj := range bundleManifests(ctx, bd) {
+					names = append(names, fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()))
+				}
+				Expect(names).To(ContainElements(
+					"Namespace//prometheus-system",
+					"ServiceAccount/prometheus-system/prometheus-operator",
+					"ClusterRole//prometheusoperator-prometheus-operator-8f46b758f",
+					"ClusterRole//prometheusoperator-prometheus-operator-6d448f9877",
+					"ClusterRoleBinding//prometheusoperator-prometheus-operator-8f46b758f",
+					"ClusterRoleBinding//prometheusoperator-prometheus-operator-6d448f9877",
+					"Deployment/prometheus-system/prometheus-operator",
+				))
+
+				By("removing the config")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				operator.Spec.Config = nil
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the same bundleDeployment installs the bundle image again")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.GetUID()).To(Equal(uid))
+				Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeImage))
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(image))
+				Expect(bd.Spec.Template.Spec.Source.ConfigMaps).To(BeEmpty())
+
+				By("checking the manifests that are no longer read are deleted")
+				for _, source := range configMaps {
+					err := cl.Get(ctx, types.NamespacedName{Namespace: controllers.DefaultBundleManifestsNamespace, Name: source.ConfigMap.Name}, &corev1.ConfigMap{})
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				}
+			})
+		})
+		When("install defaults exist", func() {
+			var defaults *operatorsv1alpha1.InstallDefaults
+			BeforeEach(func() {
+				By("initializing cluster state")
+				defaults = &operatorsv1alpha1.InstallDefaults{
+					ObjectMeta: metav1.ObjectMeta{Name: operatorsv1alpha1.InstallDefaultsName},
+					Spec: operatorsv1alpha1.InstallDefaultsSpec{
+						Config: &operatorsv1alpha1.DeploymentConfig{
+							Env: []corev1.EnvVar{
+								{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+								{Name: "NO_PROXY", Value: ".cluster.local"},
+							},
+							NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+							Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, defaults)).To(Succeed())
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						Config: &operatorsv1alpha1.DeploymentConfig{
+							Env:          []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://other-proxy:3128"}},
+							NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(cl.Delete(ctx, defaults)).To(Succeed())
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(cl.Delete(ctx, bd)).To(Succeed())
+			})
+			It("merges the config of the operator over the defaults", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the deployment installed by the BD")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				pod := renderedDeployment(ctx, bd).Spec.Template.Spec
+				Expect(pod.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/infra": ""}))
+				Expect(pod.Containers[0].Env).To(Equal([]corev1.EnvVar{
+					{Name: "HTTPS_PROXY", Value: "http://other-proxy:3128"},
+					{Name: "NO_PROXY", Value: ".cluster.local"},
+				}))
+
+				By("checking the effective config")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.EffectiveConfig).To(Equal(&operatorsv1alpha1.DeploymentConfig{
+					Env: []corev1.EnvVar{
+						{Name: "HTTPS_PROXY", Value: "http://other-proxy:3128"},
+						{Name: "NO_PROXY", Value: ".cluster.local"},
+					},
+					NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+					Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+				}))
+			})
+			It("only renders the bundle for the defaults when they change its deployments", func() {
+				By("leaving the deployments to the defaults")
+				operator.Spec.Config = nil
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the BD installs the rendered deployment")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeConfigMaps))
+				Expect(renderedDeployment(ctx, bd).Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
+
+				By("emptying the config of the defaults")
+				defaults.Spec.Config = &operatorsv1alpha1.DeploymentConfig{}
+				Expect(cl.Update(ctx, defaults)).To(Succeed())
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the BD installs the bundle image again")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeImage))
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+			})
+			It("pulls the bundle image with the pull secret of the defaults, unless the operator has its own", func() {
+				By("installing the bundle image without config")
+				operator.Spec.Config = nil
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("setting a pull secret on the defaults")
+				defaults.Spec.Config = nil
+				defaults.Spec.PullSecret = "registry-credentials"
+				Expect(cl.Update(ctx, defaults)).To(Succeed())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the image source of the BD")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.ImagePullSecretName).To(Equal("registry-credentials"))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.EffectivePullSecret).To(Equal("registry-credentials"))
+
+				By("setting a pull secret on the operator")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				operator.Spec.PullSecret = "prometheus-credentials"
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the image source of the BD")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.ImagePullSecretName).To(Equal("prometheus-credentials"))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.EffectivePullSecret).To(Equal("prometheus-credentials"))
+
+				By("configuring the deployments of the operator")
+				operator.Spec.Config = &operatorsv1alpha1.DeploymentConfig{NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""}}
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking no pull secret is reported for the rendered bundle")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Type).To(Equal(rukpakv1alpha1.SourceTypeConfigMaps))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.EffectivePullSecret).To(BeEmpty())
+			})
+			It("uses the progress deadline of the defaults", func() {
+				By("setting a progress deadline on the defaults")
+				defaults.Spec.ProgressDeadlineSeconds = pointer.Int32(600)
+				Expect(cl.Update(ctx, defaults)).To(Succeed())
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(res.RequeueAfter).To(BeNumerically("<=", 600*time.Second))
+
+				By("checking the effective progress deadline")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.EffectiveProgressDeadlineSeconds).To(Equal(pointer.Int32(600)))
+			})
+		})
+		When("the operator patches the objects of the bundle", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						Patches: []operatorsv1alpha1.ManifestPatch{
+							{
+								Target: operatorsv1alpha1.PatchTarget{Group: "apps", Kind: "Deployment", Name: "prometheus-operator"},
+								Patch:  `{"metadata":{"annotations":{"example.com/patched":"true"}}}`,
+							},
+							{
+								Target: operatorsv1alpha1.PatchTarget{Kind: "Service", Name: "prometheus-metrics"},
+								Type:   operatorsv1alpha1.PatchTypeJSON6902,
+								Patch:  `[{"op":"add","path":"/metadata/annotations","value":{}}]`,
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			AfterEach(func() {
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(client.IgnoreNotFound(cl.Delete(ctx, bd))).To(Succeed())
+			})
+			It("applies the patches to the rendered objects and reports those that match nothing", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the deployment installed by the BD")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Config.Raw).To(BeEmpty())
+				Expect(renderedDeployment(ctx, bd).GetAnnotations()).To(HaveKeyWithValue("example.com/patched", "true"))
+
+				By("checking the patches applied condition")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePatchesApplied)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPatchesNotMatched))
+				Expect(cond.Message).To(Equal("patches match no object of the bundle: patches[1] targeting Service prometheus-metrics"))
+			})
+			It("checks and previews the patched objects before installing them", func() {
+				By("patching the rules the bundle grants")
+				operator.Spec.Patches = []operatorsv1alpha1.ManifestPatch{{
+					Target: operatorsv1alpha1.PatchTarget{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+					Type:   operatorsv1alpha1.PatchTypeJSON6902,
+					Patch:  `[{"op":"add","path":"/rules/-","value":{"apiGroups":[""],"resources":["secrets"],"verbs":["delete"]}}]`,
+				}}
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				check := &recordingCheck{}
+				reconciler.PreflightChecks = []preflight.Check{check}
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the preflight checks saw the patched rules")
+				patchedRule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"delete"}}
+				Expect(check.bundle).NotTo(BeNil())
+				var clusterRoles int
+				for _, obj := range check.bundle.Objects {
+					if obj.GetKind() != "ClusterRole" {
+						continue
+					}
+					clusterRoles++
+					role := &rbacv1.ClusterRole{}
+					Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, role)).To(Succeed())
+					Expect(role.Rules).To(ContainElement(patchedRule))
+				}
+				Expect(clusterRoles).To(Equal(2))
+
+				By("checking the preview shows the patched rules")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.PermissionPreview).NotTo(BeNil())
+				Expect(operator.Status.PermissionPreview.ClusterRules).To(ContainElement(patchedRule))
+			})
+			It("does not install the bundle when a patch fails to apply", func() {
+				By("patching a path that does not exist")
+				operator.Spec.Patches = []operatorsv1alpha1.ManifestPatch{{
+					Target: operatorsv1alpha1.PatchTarget{Group: "apps", Kind: "Deployment", Name: "prometheus-operator"},
+					Type:   operatorsv1alpha1.PatchTypeJSON6902,
+					Patch:  `[{"op":"replace","path":"/spec/replicas/count","value":2}]`,
+				}}
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking no bundleDeployment was created")
+				err = cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, &rukpakv1alpha1.BundleDeployment{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				By("checking the failure is reported")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePatchesApplied)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPatchFailed))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationFailed))
+			})
+		})
+		When("the bundleDeployment is checked before it is applied", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				err := cl.Create(ctx, operator)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("reports the preflight checks that passed", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPreflightChecksPassed))
+				Expect(cond.Message).To(Equal("preflight checks passed: Ownership, CRDUpgradeSafety, ServiceAccountPermissions, PermissionEscalation, DryRunApply"))
+
+				bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: opKey.Name}}
+				Expect(cl.Delete(ctx, bd)).To(Succeed())
+			})
+			It("does not take over a bundleDeployment controlled by something else", func() {
+				By("creating a bundleDeployment controlled by something else")
+				bd := &rukpakv1alpha1.BundleDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: opKey.Name,
+						OwnerReferences: []metav1.OwnerReference{{
+							APIVersion: "example.com/v1",
+							Kind:       "Widget",
+							Name:       "widget",
+							UID:        "widget-uid",
+							Controller: pointer.Bool(true),
+						}},
+					},
+					Spec: rukpakv1alpha1.BundleDeploymentSpec{
+						ProvisionerClassName: "core-rukpak-io-plain",
+						Template: &rukpakv1alpha1.BundleTemplate{
+							Spec: rukpakv1alpha1.BundleSpec{
+								ProvisionerClassName: "core-rukpak-io-registry",
+								Source: rukpakv1alpha1.BundleSource{
+									Type:  rukpakv1alpha1.SourceTypeImage,
+									Image: &rukpakv1alpha1.ImageSource{Ref: "quay.io/example/widget-bundle:v1"},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, bd)).To(Succeed())
+				defer func() {
+					Expect(cl.Delete(ctx, bd)).To(Succeed())
+				}()
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(time.Minute))
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPreflightChecksFailed))
+				Expect(cond.Message).To(Equal(fmt.Sprintf("preflight check Ownership failed: BundleDeployment %q is already controlled by Widget \"widget\"", opKey.Name)))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+				Expect(cond.Message).To(Equal("installation has not been attempted as preflight checks failed"))
+
+				By("checking the bundleDeployment was left untouched")
+				Expect(cl.Get(ctx, client.ObjectKeyFromObject(bd), bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/example/widget-bundle:v1"))
+			})
+			It("does not install with a service account that does not exist", func() {
+				By("installing with a service account")
+				operator.Spec.PreflightServiceAccount = &operatorsv1alpha1.ServiceAccountReference{Name: "installer"}
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(time.Minute))
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPreflightChecksFailed))
+				Expect(cond.Message).To(Equal(`preflight check ServiceAccountPermissions failed: service account "prometheus-system/installer" not found; ` +
+					`preflight check PermissionEscalation failed: service account "prometheus-system/installer" not found`))
+
+				By("checking the bundleDeployment was not created")
+				err = cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, &rukpakv1alpha1.BundleDeployment{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+			It("runs the preflight checks it is configured with", func() {
+				reconciler.PreflightChecks = []preflight.Check{failingCheck{}}
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Message).To(Equal("preflight check Failing failed: not today"))
+
+				By("checking no bundleDeployment was created")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				err = cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+			It("does not install while a preflight check cannot complete", func() {
+				reconciler.PreflightChecks = []preflight.Check{unknownCheck{}}
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(time.Minute))
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPreflightStatusUnknown))
+				Expect(cond.Message).To(Equal("preflight check Unknown could not complete: not allowed"))
+
+				By("checking no bundleDeployment was created")
+				err = cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, &rukpakv1alpha1.BundleDeployment{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+			It("installs when preflight checks pass with a warning", func() {
+				reconciler.PreflightChecks = []preflight.Check{warningCheck{}}
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(ctrl.Result{}))
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPreflightChecksPassed))
+				Expect(cond.Message).To(Equal("preflight checks passed: Warning; preflight check Warning passed with a warning: not everything was checked"))
+
+				By("checking the bundleDeployment was created")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(cl.Delete(ctx, bd)).To(Succeed())
+			})
+			It("does not run the preflight checks again once the bundleDeployment is applied", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reporting the bundleDeployment as installed")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				defer func() {
+					Expect(cl.Delete(ctx, bd)).To(Succeed())
+				}()
+				bd.Status.ObservedGeneration = bd.GetGeneration()
+				apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+					Type:   rukpakv1alpha1.TypeInstalled,
+					Status: metav1.ConditionTrue,
+					Reason: rukpakv1alpha1.ReasonInstallationSucceeded,
+				})
+				Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+
+				By("configuring a failing preflight check")
+				reconciler.PreflightChecks = []preflight.Check{failingCheck{}}
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(ctrl.Result{}))
+
+				By("checking the install status was left alone")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.InstalledBundleResource).NotTo(BeEmpty())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypePreflightPassed)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Message).To(Equal("preflight checks passed: Ownership, CRDUpgradeSafety, ServiceAccountPermissions, PermissionEscalation, DryRunApply"))
+			})
+		})
+		When("the selected bundle's image ref cannot be parsed", func() {
+			const pkgName = "badimage"
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: pkgName},
+				}
+				err := cl.Create(ctx, operator)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("sets resolution failure status and returns an error", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).To(MatchError(ContainSubstring(`error determining bundle path for entity`)))
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("Checking the status fields")
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(""))
+				Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
+				Expect(cond.Message).To(ContainSubstring(`error determining bundle path for entity`))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
 				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
 				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationStatusUnknown))
 				Expect(cond.Message).To(Equal("installation has not been attempted as resolution failed"))
@@ -765,6 +2686,116 @@ var _ = Describe("Operator Controller Test", func() {
 				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
 			})
 		})
+		When("the operator specifies a package requiring an API served by the cluster", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "widgets"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				reconciler.Resolver = resolution.NewOperatorResolver(cl, testEntitySource, resolution.WithDiscovery(&fakediscovery.FakeDiscovery{
+					Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+						GroupVersion: "apps/v1",
+						APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}},
+					}}},
+				}))
+			})
+			It("reports the dependency as satisfied by the cluster", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the resolved bundle")
+				Expect(operator.Status.ResolvedBundle).NotTo(BeNil())
+				Expect(operator.Status.ResolvedBundle.Dependencies).To(BeEmpty())
+				Expect(operator.Status.ResolvedBundle.ClusterProvidedAPIs).To(Equal([]operatorsv1alpha1.GroupVersionKind{
+					{Group: "apps", Version: "v1", Kind: "Deployment"},
+				}))
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			})
+		})
+		When("the operator specifies a version older than the latest one", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						Version:     "0.37.0",
+					},
+				}
+				err := cl.Create(ctx, operator)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("reports the newer versions as available upgrades", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("Checking the status fields")
+				Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"))
+				Expect(operator.Status.AvailableUpgrades).To(Equal([]operatorsv1alpha1.AvailableUpgrade{
+					{Version: "0.47.0", Channel: "beta"},
+				}))
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeAvailable)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonNewerVersionsAvailable))
+				Expect(cond.Message).To(Equal("newer versions are available: 0.47.0 in channel \"beta\""))
+			})
+			It("reports the latest newer versions of each channel, up to a limit", func() {
+				By("adding several newer versions in several channels")
+				entities := map[deppy.Identifier]input.Entity{}
+				for _, bundle := range []struct{ version, channel string }{
+					{"0.37.0", "beta"}, {"0.40.0", "beta"}, {"0.47.0", "beta"},
+					{"0.45.0", "stable"}, {"0.46.0", "stable"}, {"0.48.0", "stable"}, {"0.49.0", "stable"}, {"0.50.0", "stable"}, {"0.51.0", "stable"},
+				} {
+					id := deppy.IdentifierFromString(fmt.Sprintf("operatorhub/prometheus/%s/%s", bundle.channel, bundle.version))
+					entities[id] = *input.NewEntity(id, map[string]string{
+						"olm.bundle.path": fmt.Sprintf(`"quay.io/operatorhubio/prometheus:v%s"`, bundle.version),
+						"olm.channel":     fmt.Sprintf(`{"channelName":%q,"priority":0}`, bundle.channel),
+						"olm.package":     fmt.Sprintf(`{"packageName":"prometheus","version":%q}`, bundle.version),
+						"olm.gvk":         `[]`,
+					})
+				}
+				reconciler.Resolver = resolution.NewOperatorResolver(cl, input.NewCacheQuerier(entities))
+
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the available upgrades")
+				Expect(operator.Status.AvailableUpgrades).To(Equal([]operatorsv1alpha1.AvailableUpgrade{
+					{Version: "0.47.0", Channel: "beta"},
+					{Version: "0.40.0", Channel: "beta"},
+					{Version: "0.51.0", Channel: "stable"},
+					{Version: "0.50.0", Channel: "stable"},
+					{Version: "0.49.0", Channel: "stable"},
+					{Version: "0.48.0", Channel: "stable"},
+					{Version: "0.46.0", Channel: "stable"},
+				}))
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeUpgradeAvailable)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Message).To(Equal("newer versions are available: 0.47.0 in channel \"beta\", 0.40.0 in channel \"beta\", " +
+					"0.51.0 in channel \"stable\", 0.50.0 in channel \"stable\", 0.49.0 in channel \"stable\", 0.48.0 in channel \"stable\", 0.46.0 in channel \"stable\""))
+			})
+		})
 		When("the operator specifies a package that exists within a channel but no version specified", func() {
 			var pkgName string
 			var pkgVer string
@@ -1043,18 +3074,96 @@ func verifyConditionsInvariants(op *operatorsv1alpha1.Operator) {
 	}
 }
 
+// failingCheck is a preflight check that always fails.
+type failingCheck struct{}
+
+func (failingCheck) Name() string {
+	return "Failing"
+}
+
+func (failingCheck) Check(context.Context, *preflight.Bundle) error {
+	return errors.New("not today")
+}
+
+// unknownCheck is a preflight check that can never tell whether the bundle passes it.
+type unknownCheck struct{}
+
+func (unknownCheck) Name() string {
+	return "Unknown"
+}
+
+func (unknownCheck) Check(context.Context, *preflight.Bundle) error {
+	return &preflight.UnknownError{Err: errors.New("not allowed")}
+}
+
+// warningCheck is a preflight check that always passes with a warning.
+type warningCheck struct{}
+
+func (warningCheck) Name() string {
+	return "Warning"
+}
+
+func (warningCheck) Check(context.Context, *preflight.Bundle) error {
+	return &preflight.WarningError{Err: errors.New("not everything was checked")}
+}
+
+// recordingCheck is a preflight check that always passes and records the bundle it checked.
+type recordingCheck struct {
+	bundle *preflight.Bundle
+}
+
+func (*recordingCheck) Name() string {
+	return "Recording"
+}
+
+func (c *recordingCheck) Check(_ context.Context, bundle *preflight.Bundle) error {
+	c.bundle = bundle
+	return nil
+}
+
+// failingListClient is a client whose lists of unstructured objects always fail.
+type failingListClient struct {
+	client.Client
+}
+
+func (c *failingListClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*unstructured.UnstructuredList); ok {
+		return errors.New("not today")
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
 var testEntitySource = input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
 	"operatorhub/prometheus/0.37.0": *input.NewEntity("operatorhub/prometheus/0.37.0", map[string]string{
 		"olm.bundle.path": `"quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"`,
 		"olm.channel":     `{"channelName":"beta","priority":0}`,
 		"olm.package":     `{"packageName":"prometheus","version":"0.37.0"}`,
 		"olm.gvk":         `[]`,
+		"olm.bundle.object": csvBundleObject(
+			`[{"apiGroups":[""],"resources":["configmaps"],"verbs":["get","list"]}]`,
+			`[{"apiGroups":[""],"resources":["namespaces"],"verbs":["list"]}]`,
+		),
 	}),
 	"operatorhub/prometheus/0.47.0": *input.NewEntity("operatorhub/prometheus/0.47.0", map[string]string{
-		"olm.bundle.path": `"quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"`,
-		"olm.channel":     `{"channelName":"beta","priority":0,"replaces":"prometheusoperator.0.37.0"}`,
-		"olm.package":     `{"packageName":"prometheus","version":"0.47.0"}`,
-		"olm.gvk":         `[]`,
+		"olm.bundle.path":  `"quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"`,
+		"olm.bundle.name":  `"prometheusoperator.0.47.0"`,
+		"olm.catalog.name": `"operatorhub"`,
+		"olm.channel":      `{"channelName":"beta","priority":0,"replaces":"prometheusoperator.0.37.0"}`,
+		"olm.package":      `{"packageName":"prometheus","version":"0.47.0"}`,
+		"olm.gvk":          `[]`,
+		"olm.bundle.object": csvBundleObject(
+			`[{"apiGroups":[""],"resources":["configmaps","secrets"],"verbs":["get"]}]`,
+			`[{"apiGroups":[""],"resources":["namespaces"],"verbs":["list"]}]`,
+		),
+	}),
+	"operatorhub/widgets/1.0.0": *input.NewEntity("operatorhub/widgets/1.0.0", map[string]string{
+		"olm.bundle.path":  `"quay.io/operatorhubio/widgets:v1.0.0"`,
+		"olm.bundle.name":  `"widgets.v1.0.0"`,
+		"olm.catalog.name": `"operatorhub"`,
+		"olm.channel":      `{"channelName":"stable","priority":0}`,
+		"olm.package":      `{"packageName":"widgets","version":"1.0.0"}`,
+		"olm.gvk":          `[]`,
+		"olm.gvk.required": `[{"group":"apps","kind":"Deployment","version":"v1"}]`,
 	}),
 	"operatorhub/badimage/0.1.0": *input.NewEntity("operatorhub/badimage/0.1.0", map[string]string{
 		"olm.bundle.path": `{"name": "quay.io/operatorhubio/badimage:v0.1.0"}`,
@@ -1062,3 +3171,42 @@ var testEntitySource = input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
 		"olm.gvk":         `[]`,
 	}),
 })
+
+// renderedDeployment returns the Deployment among the manifests rendered for the BundleDeployment.
+func renderedDeployment(ctx context.Context, bd *rukpakv1alpha1.BundleDeployment) *appsv1.Deployment {
+	objects := bundleManifests(ctx, bd)
+	Expect(objects).NotTo(BeEmpty())
+	deployment := &appsv1.Deployment{}
+	Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(objects[len(objects)-1].Object, deployment)).To(Succeed())
+	Expect(deployment.Kind).To(Equal("Deployment"))
+	return deployment
+}
+
+// bundleManifests returns the objects of the manifests rendered for the BundleDeployment.
+func bundleManifests(ctx context.Context, bd *rukpakv1alpha1.BundleDeployment) []unstructured.Unstructured {
+	var objects []unstructured.Unstructured
+	for _, source := range bd.Spec.Template.Spec.Source.ConfigMaps {
+		Expect(source.Path).To(Equal("manifests"))
+		cm := &corev1.ConfigMap{}
+		Expect(cl.Get(ctx, types.NamespacedName{Namespace: controllers.DefaultBundleManifestsNamespace, Name: source.ConfigMap.Name}, cm)).To(Succeed())
+		Expect(cm.Immutable).To(Equal(pointer.Bool(true)))
+		for i := 0; i < len(cm.Data); i++ {
+			data, ok := cm.Data[fmt.Sprintf("object-%d.yaml", len(objects))]
+			Expect(ok).To(BeTrue())
+			obj := unstructured.Unstructured{}
+			Expect(yaml.Unmarshal([]byte(data), &obj.Object)).To(Succeed())
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// csvBundleObject returns the olm.bundle.object property of a bundle whose ClusterServiceVersion grants
+// the given namespaced and cluster-wide rules to its operator, deployed as prometheus-operator.
+func csvBundleObject(rules, clusterRules string) string {
+	csv := fmt.Sprintf(`{"apiVersion":"operators.coreos.com/v1alpha1","kind":"ClusterServiceVersion","metadata":{"name":"prometheusoperator"},`+
+		`"spec":{"install":{"strategy":"deployment","spec":{"permissions":[{"serviceAccountName":"prometheus-operator","rules":%s}],`+
+		`"clusterPermissions":[{"serviceAccountName":"prometheus-operator","rules":%s}],`+
+		`"deployments":[{"name":"prometheus-operator","spec":{"template":{"spec":{"containers":[{"name":"manager"}]}}}}]}}}}`, rules, clusterRules)
+	return fmt.Sprintf(`[{"data":%q}]`, base64.StdEncoding.EncodeToString([]byte(csv)))
+}
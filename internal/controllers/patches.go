@@ -0,0 +1,52 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/patches"
+)
+
+// applyPatches applies the patches the Operator declares to the rendered objects of its bundle, in
+// order, and reports in the PatchesApplied condition whether each of them matched an object of the
+// bundle. It fails if a patch does not apply cleanly, in which case the bundle is not installed.
+func applyPatches(op *operatorsv1alpha1.Operator, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	if len(op.Spec.Patches) == 0 {
+		setPatchesAppliedStatusConditionApplied(&op.Status.Conditions, "no patches to apply", op.GetGeneration())
+		return objects, nil
+	}
+	patched, unmatched, err := patches.Apply(objects, op.Spec.Patches)
+	if err != nil {
+		setPatchesAppliedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return nil, err
+	}
+	if len(unmatched) > 0 {
+		descriptions := make([]string, 0, len(unmatched))
+		for _, i := range unmatched {
+			descriptions = append(descriptions, fmt.Sprintf("patches[%d] targeting %s", i, patches.Describe(op.Spec.Patches[i].Target)))
+		}
+		setPatchesAppliedStatusConditionNotMatched(&op.Status.Conditions, fmt.Sprintf("patches match no object of the bundle: %s", strings.Join(descriptions, ", ")), op.GetGeneration())
+		return patched, nil
+	}
+	setPatchesAppliedStatusConditionApplied(&op.Status.Conditions, "all patches applied to objects of the bundle", op.GetGeneration())
+	return patched, nil
+}
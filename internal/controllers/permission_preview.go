@@ -0,0 +1,106 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/patches"
+	"github.com/operator-framework/operator-controller/internal/permissions"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+// setPermissionPreview previews the permissions the objects of the resolved bundle, as rendered and
+// patched for the Operator, grant to its operator, compared to those granted by the installed bundle,
+// before the resolved bundle is installed. The objects are nil if unknown.
+func (r *OperatorReconciler) setPermissionPreview(ctx context.Context, op *operatorsv1alpha1.Operator, bundleImage string, objects []unstructured.Unstructured, installedImage string) {
+	preview := &operatorsv1alpha1.PermissionPreview{BundleResource: bundleImage}
+	op.Status.PermissionPreview = preview
+	upgrade := installedImage != "" && installedImage != bundleImage
+	if upgrade {
+		preview.InstalledBundleResource = installedImage
+	}
+
+	granted, err := bundlePermissions(op, objects)
+	if err != nil {
+		preview.Summary = fmt.Sprintf("the permissions granted by the bundle could not be determined: %v", err)
+		return
+	}
+	if granted == nil {
+		preview.Summary = "the permissions granted by the bundle are unknown, as the catalog does not provide its objects"
+		return
+	}
+	preview.ClusterRules = granted.ClusterRules
+	for _, namespace := range granted.Namespaces() {
+		preview.NamespaceRules = append(preview.NamespaceRules, operatorsv1alpha1.NamespacePolicyRules{
+			Namespace: namespace,
+			Rules:     granted.NamespaceRules[namespace],
+		})
+	}
+	preview.Summary = fmt.Sprintf("the bundle grants %s", granted.Summary())
+
+	if !upgrade {
+		return
+	}
+	installedEntity, err := r.Resolver.BundleByPath(ctx, op.Spec.PackageName, installedImage)
+	if err != nil {
+		preview.Summary = fmt.Sprintf("%s; the permissions granted by the installed bundle could not be determined: %v", preview.Summary, err)
+		return
+	}
+	// the installed bundle is compared as the Operator would install it now
+	installedObjects, err := installedBundleObjects(op, installedEntity)
+	if err != nil {
+		preview.Summary = fmt.Sprintf("%s; the permissions granted by the installed bundle could not be determined: %v", preview.Summary, err)
+		return
+	}
+	installed, err := bundlePermissions(op, installedObjects)
+	if err != nil || installed == nil {
+		preview.Summary = fmt.Sprintf("%s; the permissions granted by the installed bundle could not be determined", preview.Summary)
+		return
+	}
+	preview.AddedPermissions, preview.RemovedPermissions = permissions.Diff(installed, granted)
+	preview.Summary = fmt.Sprintf("%s; compared to the installed bundle, permissions added: %d, removed: %d",
+		preview.Summary, len(preview.AddedPermissions), len(preview.RemovedPermissions))
+}
+
+// installedBundleObjects returns the objects of the bundle rendered and patched for the Operator, the
+// way renderBundle and applyPatches do without reporting anything, or nil if they are unknown.
+func installedBundleObjects(op *operatorsv1alpha1.Operator, bundleEntity *entity.BundleEntity) ([]unstructured.Unstructured, error) {
+	installNamespace, bundleNamespace, err := installNamespaces(op, bundleEntity)
+	if err != nil {
+		return nil, err
+	}
+	objects, _, err := renderBundle(op, bundleEntity, installNamespace, bundleNamespace)
+	if err != nil || len(op.Spec.Patches) == 0 {
+		return objects, err
+	}
+	patched, _, err := patches.Apply(objects, op.Spec.Patches)
+	return patched, err
+}
+
+// bundlePermissions returns the permissions the given objects of a bundle grant to its operator, or
+// nil if they are unknown as the objects are.
+func bundlePermissions(op *operatorsv1alpha1.Operator, objects []unstructured.Unstructured) (*permissions.Permissions, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+	return permissions.ForBundle(objects, op.Status.InstallNamespace, op.Spec.WatchNamespaces)
+}
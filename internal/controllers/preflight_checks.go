@@ -0,0 +1,198 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/preflight"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+const (
+	// bundleDeploymentFieldOwner is the field manager of the BundleDeployments applied by the controller.
+	bundleDeploymentFieldOwner = "operator-controller"
+
+	// preflightRecheckInterval is how often failed preflight checks are run again, as the
+	// cluster state they depend on is not watched.
+	preflightRecheckInterval = time.Minute
+)
+
+// The CRD upgrade safety check reads the existing CRDs and lists their custom resources, which can be of any kind:
+// the permission to list them is granted through ClusterRoles aggregated into preflight-role, see
+// config/rbac/preflight_role.yaml.
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
+
+// The service account permissions check reads the service account and reviews its permissions.
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// preflightChecks returns the checks the BundleDeployment of an Operator must pass before it is applied.
+func (r *OperatorReconciler) preflightChecks() []preflight.Check {
+	if r.PreflightChecks != nil {
+		return r.PreflightChecks
+	}
+	return preflight.DefaultChecks(r.Client, bundleDeploymentFieldOwner)
+}
+
+// runPreflightChecks runs the preflight checks against the desired BundleDeployment and the objects
+// it installs, as rendered and patched for the Operator, and reports their results in the
+// PreflightPassed condition of the Operator. The objects are nil if unknown.
+// It returns true if all checks passed, possibly with warnings.
+func (r *OperatorReconciler) runPreflightChecks(ctx context.Context, op *operatorsv1alpha1.Operator, desiredBundleDeployment *unstructured.Unstructured, objects []unstructured.Unstructured) bool {
+	crds, err := bundleCRDs(objects)
+	if err != nil {
+		setPreflightPassedStatusConditionFailed(&op.Status.Conditions, fmt.Sprintf("failed to read the CRDs of the bundle: %v", err), op.GetGeneration())
+		return false
+	}
+	results, passed := preflight.Run(ctx, r.preflightChecks(), &preflight.Bundle{
+		BundleDeployment:          desiredBundleDeployment,
+		CustomResourceDefinitions: crds,
+		Objects:                   objects,
+		InstallNamespace:          op.Status.InstallNamespace,
+		WatchNamespaces:           op.Spec.WatchNamespaces,
+		ServiceAccount:            serviceAccount(op),
+	})
+	var failures, unknowns, warnings []string
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+		case preflight.IsWarning(result.Err):
+			warnings = append(warnings, fmt.Sprintf("preflight check %s passed with a warning: %v", result.Name, result.Err))
+		case preflight.IsUnknown(result.Err):
+			unknowns = append(unknowns, fmt.Sprintf("preflight check %s could not complete: %v", result.Name, result.Err))
+		default:
+			failures = append(failures, fmt.Sprintf("preflight check %s failed: %v", result.Name, result.Err))
+		}
+	}
+	if !passed {
+		if len(failures) > 0 {
+			setPreflightPassedStatusConditionFailed(&op.Status.Conditions, strings.Join(append(failures, unknowns...), "; "), op.GetGeneration())
+			return false
+		}
+		setPreflightPassedStatusConditionUnknown(&op.Status.Conditions, strings.Join(unknowns, "; "), op.GetGeneration())
+		return false
+	}
+
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	message := "no preflight checks to run"
+	if len(names) > 0 {
+		message = fmt.Sprintf("preflight checks passed: %s", strings.Join(names, ", "))
+	}
+	if len(warnings) > 0 {
+		message = strings.Join(append([]string{message}, warnings...), "; ")
+	}
+	setPreflightPassedStatusConditionPassed(&op.Status.Conditions, message, op.GetGeneration())
+	return true
+}
+
+// bundleDeploymentApplied returns true if the desired BundleDeployment is already applied. The
+// preflight checks are only run before a BundleDeployment is applied or changed: they are expensive,
+// and failing them afterwards would not uninstall anything.
+func (r *OperatorReconciler) bundleDeploymentApplied(ctx context.Context, desiredBundleDeployment *unstructured.Unstructured) (bool, error) {
+	existingBundleDeployment, err := r.existingBundleDeploymentUnstructured(ctx, desiredBundleDeployment.GetName())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return equality.Semantic.DeepDerivative(desiredBundleDeployment, existingBundleDeployment), nil
+}
+
+// keepPreflightPassedCondition reports the result of the preflight checks that were run before the
+// BundleDeployment of the Operator was applied, for the current generation of the Operator.
+func keepPreflightPassedCondition(op *operatorsv1alpha1.Operator) {
+	if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypePreflightPassed); cond != nil && cond.Status == metav1.ConditionTrue {
+		setPreflightPassedStatusConditionPassed(&op.Status.Conditions, cond.Message, op.GetGeneration())
+		return
+	}
+	setPreflightPassedStatusConditionUnknown(&op.Status.Conditions, "preflight checks are not run as the bundledeployment is already applied", op.GetGeneration())
+}
+
+// bundleObjects returns the objects of the bundle, as provided by the catalog, or nil if the catalog
+// does not provide all of them, as only the objects embedded in the catalog can be read.
+func bundleObjects(bundleEntity *entity.BundleEntity) ([]unstructured.Unstructured, error) {
+	bundleObjects, err := bundleEntity.BundleObjects()
+	if err != nil {
+		return nil, err
+	}
+	var objects []unstructured.Unstructured
+	for _, bundleObject := range bundleObjects {
+		if bundleObject.IsRef() {
+			return nil, nil
+		}
+		data, err := bundleObject.GetData(nil, "")
+		if err != nil {
+			return nil, err
+		}
+		obj := unstructured.Unstructured{}
+		if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), len(data)).Decode(&obj.Object); err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// bundleCRDs returns the CRDs found among the objects of the bundle.
+func bundleCRDs(objects []unstructured.Unstructured) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []apiextensionsv1.CustomResourceDefinition
+	for i := range objects {
+		obj := &objects[i]
+		if obj.GroupVersionKind() != apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+			continue
+		}
+		crd := apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &crd); err != nil {
+			return nil, err
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// serviceAccount returns the service account the preflight checks verify the permissions of for the
+// Operator, looked up in the install namespace unless its namespace is given, or nil if none is given.
+func serviceAccount(op *operatorsv1alpha1.Operator) *types.NamespacedName {
+	if op.Spec.PreflightServiceAccount == nil {
+		return nil
+	}
+	namespace := op.Spec.PreflightServiceAccount.Namespace
+	if namespace == "" {
+		namespace = op.Status.InstallNamespace
+	}
+	return &types.NamespacedName{Namespace: namespace, Name: op.Spec.PreflightServiceAccount.Name}
+}
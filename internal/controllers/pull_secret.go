@@ -0,0 +1,105 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// The Bundles a BundleDeployment unpacks report why their image cannot be pulled.
+//+kubebuilder:rbac:groups=core.rukpak.io,resources=bundles,verbs=get;list;watch
+
+// imagePullAuthFailures are fragments of the errors registries and container runtimes report
+// when an image cannot be pulled for lack of valid credentials.
+var imagePullAuthFailures = []string{
+	"unauthorized",
+	"authentication required",
+	"authorization failed",
+	"pull access denied",
+	"no basic auth credentials",
+}
+
+// pullSecret returns the name of the Secret used to pull the bundle image of the Operator:
+// its own, or else the one of the install defaults, if any.
+func pullSecret(op *operatorsv1alpha1.Operator, defaults *operatorsv1alpha1.InstallDefaults) string {
+	if op.Spec.PullSecret != "" || defaults == nil {
+		return op.Spec.PullSecret
+	}
+	return defaults.Spec.PullSecret
+}
+
+// imagePullAuthFailure returns the message reporting that the bundle image of the BundleDeployment
+// cannot be pulled for lack of valid credentials, or an empty string if it can be pulled, or fails
+// for another reason. rukpak reports an image that cannot be pulled with the structured reasons of a
+// bundle that is still, or can no longer be, unpacked: the unpack pod waits for its image to be
+// pulled, and its pull error is reported on the Unpacked condition of the Bundle. Only the message
+// of that condition tells missing or rejected credentials apart from other pull failures, as neither
+// rukpak nor the kubelet report them with a reason of their own.
+func (r *OperatorReconciler) imagePullAuthFailure(ctx context.Context, bd *rukpakv1alpha1.BundleDeployment) (string, error) {
+	if isBundleDepStale(bd) {
+		return "", nil
+	}
+	hasValidBundle := apimeta.FindStatusCondition(bd.Status.Conditions, rukpakv1alpha1.TypeHasValidBundle)
+	if hasValidBundle == nil || (hasValidBundle.Reason != rukpakv1alpha1.ReasonUnpackPending && hasValidBundle.Reason != rukpakv1alpha1.ReasonUnpackFailed) {
+		return "", nil
+	}
+
+	// the BundleDeployment unpacks its most recent Bundle
+	bundles := &rukpakv1alpha1.BundleList{}
+	if err := r.Client.List(ctx, bundles, client.MatchingLabels{
+		bundleDeploymentOwnerKindLabel: rukpakv1alpha1.BundleDeploymentKind,
+		bundleDeploymentOwnerNameLabel: bd.GetName(),
+	}); err != nil {
+		return "", err
+	}
+	var bundle *rukpakv1alpha1.Bundle
+	for i := range bundles.Items {
+		if bundle == nil || bundle.CreationTimestamp.Before(&bundles.Items[i].CreationTimestamp) {
+			bundle = &bundles.Items[i]
+		}
+	}
+	if bundle == nil {
+		return "", nil
+	}
+	unpacked := apimeta.FindStatusCondition(bundle.Status.Conditions, rukpakv1alpha1.TypeUnpacked)
+	if unpacked == nil || (unpacked.Reason != rukpakv1alpha1.ReasonUnpackPending && unpacked.Reason != rukpakv1alpha1.ReasonUnpackFailed) {
+		return "", nil
+	}
+	if !isImagePullAuthFailure(unpacked.Message) {
+		return "", nil
+	}
+	return unpacked.Message, nil
+}
+
+// isImagePullAuthFailure returns true if the message reports that an image could not be pulled
+// because the registry rejected the credentials, or required some.
+func isImagePullAuthFailure(message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range imagePullAuthFailures {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
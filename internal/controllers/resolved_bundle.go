@@ -0,0 +1,83 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+// resolvedBundleStatus describes the resolved bundle, its dependencies and the APIs it requires
+// that are provided by the cluster.
+func resolvedBundleStatus(bundle *entity.BundleEntity, dependencies []*entity.BundleEntity, clusterProvidedGVKs []entity.GVK) (*operatorsv1alpha1.ResolvedBundle, error) {
+	metadata, err := bundleMetadata(bundle)
+	if err != nil {
+		return nil, err
+	}
+	resolvedBundle := &operatorsv1alpha1.ResolvedBundle{BundleMetadata: *metadata}
+	for _, dependency := range dependencies {
+		dependencyMetadata, err := bundleMetadata(dependency)
+		if err != nil {
+			return nil, err
+		}
+		resolvedBundle.Dependencies = append(resolvedBundle.Dependencies, *dependencyMetadata)
+	}
+	for _, gvk := range clusterProvidedGVKs {
+		resolvedBundle.ClusterProvidedAPIs = append(resolvedBundle.ClusterProvidedAPIs, operatorsv1alpha1.GroupVersionKind{
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Kind:    gvk.Kind,
+		})
+	}
+	return resolvedBundle, nil
+}
+
+// bundleMetadata describes the given bundle entity.
+func bundleMetadata(bundle *entity.BundleEntity) (*operatorsv1alpha1.BundleMetadata, error) {
+	name, err := bundle.BundleName()
+	if err != nil {
+		return nil, err
+	}
+	packageName, err := bundle.PackageName()
+	if err != nil {
+		return nil, err
+	}
+	version, err := bundle.Version()
+	if err != nil {
+		return nil, err
+	}
+	channel, err := bundle.ChannelName()
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := bundle.CatalogName()
+	if err != nil {
+		return nil, err
+	}
+	image, err := bundle.BundlePath()
+	if err != nil {
+		return nil, err
+	}
+	return &operatorsv1alpha1.BundleMetadata{
+		Name:    name,
+		Package: packageName,
+		Version: version.String(),
+		Channel: channel,
+		Catalog: catalog,
+		Image:   image,
+	}, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// trackRollback records the last bundle that was successfully installed and found healthy for the
+// Operator. If the Operator opted in to rollbacks and a different bundle fails for good, that bundle
+// is recorded as failed, which pins resolution to the last known-good bundle until the spec changes
+// or the catalog provides a bundle that may fix the failure. The returned result requeues the
+// Operator right away for the rollback to happen, or when the bundle is due to become healthy.
+func trackRollback(op *operatorsv1alpha1.Operator, result ctrl.Result) ctrl.Result {
+	// a failed bundle is only avoided for the generation of the spec it failed with
+	if failed := op.Status.FailedBundle; failed != nil && failed.Generation != op.GetGeneration() {
+		op.Status.FailedBundle = nil
+	}
+
+	resolved := op.Status.ResolvedBundle
+	if resolved == nil {
+		return result
+	}
+	if apimeta.IsStatusConditionTrue(op.Status.Conditions, operatorsv1alpha1.TypeInstalled) && !isUnhealthy(op) {
+		lastKnownGood := resolved.BundleMetadata
+		op.Status.LastKnownGoodBundle = &lastKnownGood
+		return result
+	}
+
+	lastKnownGood := op.Status.LastKnownGoodBundle
+	if !op.Spec.RollbackOnFailure || lastKnownGood == nil || lastKnownGood.Image == resolved.Image {
+		return result
+	}
+	failure, remaining := rollbackFailure(op, time.Now())
+	if failure == "" {
+		if remaining > 0 && (result.RequeueAfter == 0 || remaining < result.RequeueAfter) {
+			result.RequeueAfter = remaining
+		}
+		return result
+	}
+	op.Status.FailedBundle = &operatorsv1alpha1.FailedBundle{
+		BundleMetadata: resolved.BundleMetadata,
+		Generation:     op.GetGeneration(),
+		FailureTime:    metav1.Now(),
+		Message:        failure,
+	}
+	result.Requeue = true
+	return result
+}
+
+// rollbackFailure returns why the resolved bundle is rolled back from, or an empty string if it is
+// not (yet), along with the time left for it to become healthy. Only failures that are not expected
+// to go away cause a rollback: an invalid bundle, an installation that did not complete within the
+// progress deadline, or an installed bundle that did not become healthy within the progress deadline.
+func rollbackFailure(op *operatorsv1alpha1.Operator, now time.Time) (string, time.Duration) {
+	if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeProgressing); cond != nil && cond.Reason == operatorsv1alpha1.ReasonInvalidBundle {
+		return cond.Message, 0
+	}
+	attempt := op.Status.InstallAttempt
+	if attempt == nil || attempt.BundleResource != op.Status.ResolvedBundle.Image {
+		return "", 0
+	}
+	if attempt.FailureTime != nil {
+		return fmt.Sprintf("installation of %q did not complete within the progress deadline", attempt.BundleResource), 0
+	}
+	healthy := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+	deadline := op.Status.EffectiveProgressDeadlineSeconds
+	if attempt.CompletionTime == nil || !isUnhealthy(op) || deadline == nil || *deadline == 0 {
+		return "", 0
+	}
+	if remaining := attempt.StartTime.Add(time.Duration(*deadline) * time.Second).Sub(now); remaining > 0 {
+		return "", remaining
+	}
+	return fmt.Sprintf("%q did not become healthy within the progress deadline: %s", attempt.BundleResource, healthy.Message), 0
+}
+
+// isUnhealthy returns true if the workloads of the installed bundle are reported as unhealthy.
+func isUnhealthy(op *operatorsv1alpha1.Operator) bool {
+	cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeHealthy)
+	return cond != nil && cond.Reason == operatorsv1alpha1.ReasonUnhealthy
+}
+
+// installFailure returns the condition reporting that the installation of the resolved bundle
+// failed, or nil if it did not fail (yet).
+func installFailure(op *operatorsv1alpha1.Operator) *metav1.Condition {
+	if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled); cond != nil && cond.Status == metav1.ConditionFalse {
+		return cond
+	}
+	if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeProgressing); cond != nil && cond.Reason == operatorsv1alpha1.ReasonInvalidBundle {
+		return cond
+	}
+	return nil
+}
@@ -23,7 +23,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
@@ -66,6 +68,10 @@ var _ = BeforeSuite(func() {
 	Expect(cfg).NotTo(BeNil())
 
 	sch = runtime.NewScheme()
+	err = clientgoscheme.AddToScheme(sch)
+	Expect(err).NotTo(HaveOccurred())
+	err = apiextensionsv1.AddToScheme(sch)
+	Expect(err).NotTo(HaveOccurred())
 	err = operatorsv1alpha1.AddToScheme(sch)
 	Expect(err).NotTo(HaveOccurred())
 	err = rukpakv1alpha1.AddToScheme(sch)
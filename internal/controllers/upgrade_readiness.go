@@ -0,0 +1,144 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/blang/semver/v4"
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+const (
+	// upgradeableConditionType is the well-known condition type an installed operator
+	// publishes to signal whether it is ready to be upgraded.
+	upgradeableConditionType = "Upgradeable"
+
+	// bundleDeploymentOwnerNameLabel is set by rukpak on every object it creates for a
+	// BundleDeployment, and holds the name of that BundleDeployment.
+	bundleDeploymentOwnerNameLabel = "core.rukpak.io/owner-name"
+
+	// upgradeReadinessRecheckInterval is how often a blocked upgrade is re-evaluated.
+	// The objects carrying the signal are not watched, as their API may not be served.
+	upgradeReadinessRecheckInterval = time.Minute
+)
+
+// operatorConditionListGVK identifies the OperatorCondition API through which operators
+// packaged for OLM signal their upgrade readiness.
+var operatorConditionListGVK = schema.GroupVersionKind{
+	Group:   "operators.coreos.com",
+	Version: "v2",
+	Kind:    "OperatorConditionList",
+}
+
+//+kubebuilder:rbac:groups=operators.coreos.com,resources=operatorconditions,verbs=list
+
+// installedBundleImage returns the image of the bundle that is currently installed by the
+// named BundleDeployment, or an empty string if no bundle is known to be successfully installed.
+func (r *OperatorReconciler) installedBundleImage(ctx context.Context, name string) (string, error) {
+	bd := &rukpakv1alpha1.BundleDeployment{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, bd); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	if isBundleDepStale(bd) || !apimeta.IsStatusConditionTrue(bd.Status.Conditions, rukpakv1alpha1.TypeInstalled) {
+		return "", nil
+	}
+	return bundleDeploymentImage(bd), nil
+}
+
+// upgradeNotReadyCondition looks for an "Upgradeable" condition published on the
+// OperatorCondition objects installed by the named BundleDeployment. It returns the first
+// such condition that reports the bundle is not ready to be upgraded, or nil if none does.
+// Overrides set by an administrator take precedence over the conditions set by the operator.
+func (r *OperatorReconciler) upgradeNotReadyCondition(ctx context.Context, bundleDeploymentName string) (*metav1.Condition, error) {
+	operatorConditions := &unstructured.UnstructuredList{}
+	operatorConditions.SetGroupVersionKind(operatorConditionListGVK)
+	if err := r.Client.List(ctx, operatorConditions, client.MatchingLabels{bundleDeploymentOwnerNameLabel: bundleDeploymentName}); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			// the OperatorCondition API is not served, so nothing can signal readiness
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, operatorCondition := range operatorConditions.Items {
+		for _, path := range [][]string{{"spec", "overrides"}, {"spec", "conditions"}, {"status", "conditions"}} {
+			conditions, err := nestedConditions(operatorCondition.Object, path...)
+			if err != nil {
+				return nil, err
+			}
+			cond := apimeta.FindStatusCondition(conditions, upgradeableConditionType)
+			if cond == nil {
+				continue
+			}
+			if cond.Status == metav1.ConditionFalse {
+				return cond, nil
+			}
+			// the first condition found takes precedence over the remaining ones
+			break
+		}
+	}
+	return nil, nil
+}
+
+// nestedConditions reads a list of conditions found at the given path of an unstructured object.
+func nestedConditions(obj map[string]interface{}, fields ...string) ([]metav1.Condition, error) {
+	rawConditions, found, err := unstructured.NestedSlice(obj, fields...)
+	if err != nil || !found {
+		return nil, err
+	}
+	conditions := make([]metav1.Condition, 0, len(rawConditions))
+	for _, rawCondition := range rawConditions {
+		rawConditionMap, ok := rawCondition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var cond metav1.Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawConditionMap, &cond); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, nil
+}
+
+// isForcedUpgrade returns true if the Operator forces the upgrade to the version of the given bundle.
+func isForcedUpgrade(op *operatorsv1alpha1.Operator, bundleEntity *entity.BundleEntity) bool {
+	if op.Spec.ForceUpgradeVersion == "" {
+		return false
+	}
+	version, err := bundleEntity.Version()
+	if err != nil {
+		return false
+	}
+	forcedVersion, err := semver.Parse(op.Spec.ForceUpgradeVersion)
+	if err != nil {
+		return false
+	}
+	return version.Equals(forcedVersion)
+}
@@ -2,10 +2,14 @@ package validators
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/healthcheck"
+	"github.com/operator-framework/operator-controller/internal/patches"
 )
 
 type operatorCRValidatorFunc func(operator *operatorsv1alpha1.Operator) error
@@ -24,10 +28,97 @@ func validateSemver(operator *operatorsv1alpha1.Operator) error {
 	return nil
 }
 
+// validateForceUpgradeVersion validates that the version the operator forces upgrades to, if provided,
+// is a valid SemVer. Like the version, this is also validated at the CRD level.
+func validateForceUpgradeVersion(operator *operatorsv1alpha1.Operator) error {
+	if operator.Spec.ForceUpgradeVersion == "" {
+		return nil
+	}
+	if _, err := semver.Parse(operator.Spec.ForceUpgradeVersion); err != nil {
+		return fmt.Errorf("invalid .spec.forceUpgradeVersion: %w", err)
+	}
+	return nil
+}
+
+// validateHealthChecks validates that the operator's health checks are well-formed, e.g. that
+// their CEL expressions compile, which cannot be validated at the CRD level.
+func validateHealthChecks(operator *operatorsv1alpha1.Operator) error {
+	for _, check := range operator.Spec.HealthChecks {
+		if err := healthcheck.Validate(check); err != nil {
+			return fmt.Errorf("invalid .spec.healthChecks: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateInstallNamespace validates that the operator's install namespace, if provided, is a valid
+// namespace name. Like the version, this is also validated at the CRD level.
+func validateInstallNamespace(operator *operatorsv1alpha1.Operator) error {
+	if operator.Spec.InstallNamespace == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(operator.Spec.InstallNamespace); len(errs) > 0 {
+		return fmt.Errorf("invalid .spec.installNamespace: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateWatchNamespaces validates that the namespaces the operator watches are valid namespace names,
+// which the CRD does not validate for the items of the list.
+func validateWatchNamespaces(operator *operatorsv1alpha1.Operator) error {
+	for _, namespace := range operator.Spec.WatchNamespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid .spec.watchNamespaces: %q: %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// validateConfig validates that the deployment config of the operator sets valid environment variables
+// and does not define a volume twice.
+func validateConfig(operator *operatorsv1alpha1.Operator) error {
+	config := operator.Spec.Config
+	if config == nil {
+		return nil
+	}
+	for _, env := range config.Env {
+		if errs := validation.IsEnvVarName(env.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid .spec.config.env: %q: %s", env.Name, strings.Join(errs, ", "))
+		}
+	}
+	volumes := map[string]struct{}{}
+	for _, volume := range config.Volumes {
+		if errs := validation.IsDNS1123Label(volume.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid .spec.config.volumes: %q: %s", volume.Name, strings.Join(errs, ", "))
+		}
+		if _, ok := volumes[volume.Name]; ok {
+			return fmt.Errorf("invalid .spec.config.volumes: volume %q is defined more than once", volume.Name)
+		}
+		volumes[volume.Name] = struct{}{}
+	}
+	return nil
+}
+
+// validatePatches validates that the patches of the operator can be parsed, which the CRD cannot validate.
+func validatePatches(operator *operatorsv1alpha1.Operator) error {
+	for i, patch := range operator.Spec.Patches {
+		if err := patches.Validate(patch); err != nil {
+			return fmt.Errorf("invalid .spec.patches[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // ValidateOperatorSpec validates the operator spec, e.g. ensuring that .spec.version, if provided, is a valid SemVer
 func ValidateOperatorSpec(operator *operatorsv1alpha1.Operator) error {
 	validators := []operatorCRValidatorFunc{
 		validateSemver,
+		validateForceUpgradeVersion,
+		validateHealthChecks,
+		validateInstallNamespace,
+		validateWatchNamespaces,
+		validateConfig,
+		validatePatches,
 	}
 
 	// TODO: currently we only have a single validator, but more will likely be added in the future
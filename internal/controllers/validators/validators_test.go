@@ -3,6 +3,7 @@ package validators_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/operator-framework/operator-controller/api/v1alpha1"
 	"github.com/operator-framework/operator-controller/internal/controllers/validators"
@@ -30,6 +31,16 @@ var _ = Describe("Validators", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should return an error for an invalid forced upgrade version", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					ForceUpgradeVersion: "latest",
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(MatchError(ContainSubstring("invalid .spec.forceUpgradeVersion")))
+		})
+
 		It("should not return an error for empty SemVer", func() {
 			operator := &v1alpha1.Operator{
 				Spec: v1alpha1.OperatorSpec{
@@ -59,5 +70,101 @@ var _ = Describe("Validators", func() {
 			err := validators.ValidateOperatorSpec(operator)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should not return an error for valid health checks", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					HealthChecks: []v1alpha1.HealthCheck{{
+						Name:       "ready",
+						Object:     &v1alpha1.HealthCheckObject{APIVersion: "example.com/v1", Kind: "Widget", Name: "widget"},
+						Expression: `self.status.phase == "Ready"`,
+					}},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error for a health check expression that does not compile", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					HealthChecks: []v1alpha1.HealthCheck{{
+						Name:       "ready",
+						Object:     &v1alpha1.HealthCheckObject{APIVersion: "example.com/v1", Kind: "Widget", Name: "widget"},
+						Expression: `self.status.phase ==`,
+					}},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should not return an error for a valid install namespace", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					InstallNamespace: "my-operator",
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error for an invalid install namespace", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					InstallNamespace: "My.Operator",
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for an invalid watch namespace", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					WatchNamespaces: []string{"default", ""},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for an invalid environment variable in the config", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					Config: &v1alpha1.DeploymentConfig{
+						Env: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "1=1"}},
+					},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(MatchError(ContainSubstring(`invalid .spec.config.env: "1=1"`)))
+		})
+
+		It("should return an error for a volume defined twice in the config", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					Config: &v1alpha1.DeploymentConfig{
+						Volumes: []corev1.Volume{{Name: "certs"}, {Name: "certs"}},
+					},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(MatchError(`invalid .spec.config.volumes: volume "certs" is defined more than once`))
+		})
+
+		It("should return an error for a patch that cannot be parsed", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					Patches: []v1alpha1.ManifestPatch{{
+						Target: v1alpha1.PatchTarget{Kind: "Service"},
+						Type:   v1alpha1.PatchTypeJSON6902,
+						Patch:  `{"op": "remove", "path": "/spec"}`,
+					}},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(MatchError(ContainSubstring("invalid .spec.patches[0]")))
+		})
 	})
 })
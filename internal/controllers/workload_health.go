@@ -0,0 +1,243 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/healthcheck"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+const (
+	// bundleDeploymentOwnerKindLabel is set by rukpak on every object it creates, and holds the
+	// kind of the object that owns it.
+	bundleDeploymentOwnerKindLabel = "core.rukpak.io/owner-kind"
+
+	// healthRecheckInterval is how often custom health checks are re-evaluated.
+	// The objects and endpoints they inspect are not watched.
+	healthRecheckInterval = time.Minute
+)
+
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=list;watch
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=list;watch
+
+// The objects inspected by health checks can be of any kind: the permission to get them is granted
+// through ClusterRoles aggregated into health-check-role, see config/rbac/health_check_role.yaml.
+
+// WorkloadCacheSelectors restricts the cache of the workloads whose health is checked to the ones
+// rukpak installed for a BundleDeployment, rather than every Deployment of the cluster.
+// CustomResourceDefinitions are not restricted, as resolution needs all of them.
+func WorkloadCacheSelectors() cache.SelectorsByObject {
+	return cache.SelectorsByObject{
+		&appsv1.Deployment{}: {Label: labels.SelectorFromSet(labels.Set{bundleDeploymentOwnerKindLabel: rukpakv1alpha1.BundleDeploymentKind})},
+	}
+}
+
+// isBundleDeploymentWorkload returns true if the object was installed by rukpak for a BundleDeployment.
+func isBundleDeploymentWorkload(obj client.Object) bool {
+	return obj.GetLabels()[bundleDeploymentOwnerKindLabel] == rukpakv1alpha1.BundleDeploymentKind
+}
+
+// setHealthyCondition sets the Healthy condition of the Operator from the health of the
+// workloads installed by its BundleDeployment, and from the custom health checks declared by
+// the Operator and its bundle. Health is only evaluated once the bundle is installed.
+// The returned result requeues the Operator for its custom health checks to be re-evaluated.
+func (r *OperatorReconciler) setHealthyCondition(ctx context.Context, op *operatorsv1alpha1.Operator, bundleEntity *entity.BundleEntity, result ctrl.Result) (ctrl.Result, error) {
+	// invalid checks are reported right away, rather than once the bundle is installed
+	checks, err := healthChecks(op, bundleEntity)
+	if err != nil {
+		setHealthyStatusConditionInvalidHealthChecks(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return result, nil
+	}
+	if !apimeta.IsStatusConditionTrue(op.Status.Conditions, operatorsv1alpha1.TypeInstalled) {
+		setHealthyStatusConditionUnknown(&op.Status.Conditions, "health is checked once the bundle is installed", op.GetGeneration())
+		return result, nil
+	}
+	problems, err := r.workloadHealthProblems(ctx, op.GetName())
+	if err != nil {
+		setHealthyStatusConditionUnknown(&op.Status.Conditions, fmt.Sprintf("failed to check the health of installed workloads: %v", err), op.GetGeneration())
+		return result, err
+	}
+
+	checker := healthcheck.Checker{Client: r.Client}
+	for _, check := range checks {
+		if err := checker.Check(ctx, check); err != nil {
+			problems = append(problems, fmt.Sprintf("health check %q failed: %v", check.Name, err))
+		}
+	}
+	if len(checks) > 0 && (result.RequeueAfter == 0 || healthRecheckInterval < result.RequeueAfter) {
+		result.RequeueAfter = healthRecheckInterval
+	}
+
+	if len(problems) > 0 {
+		setHealthyStatusConditionUnhealthy(&op.Status.Conditions, strings.Join(problems, "; "), op.GetGeneration())
+		return result, nil
+	}
+	message := "installed workloads are healthy"
+	if len(checks) > 0 {
+		message = "installed workloads are healthy and all health checks passed"
+	}
+	setHealthyStatusConditionHealthy(&op.Status.Conditions, message, op.GetGeneration())
+	return result, nil
+}
+
+// healthChecks returns the custom health checks of the Operator: the ones declared by its bundle,
+// followed by the ones declared by the Operator. The checks declared by the Operator replace the
+// checks of the bundle that have the same name. An error is returned if any of the checks of the
+// bundle that are not replaced is invalid; the checks of the Operator are validated with its spec.
+func healthChecks(op *operatorsv1alpha1.Operator, bundleEntity *entity.BundleEntity) ([]operatorsv1alpha1.HealthCheck, error) {
+	var bundleChecks []operatorsv1alpha1.HealthCheck
+	if bundleEntity != nil {
+		var err error
+		if bundleChecks, err = bundleEntity.HealthChecks(); err != nil {
+			return nil, err
+		}
+	}
+	overridden := sets.NewString()
+	for _, check := range op.Spec.HealthChecks {
+		overridden.Insert(check.Name)
+	}
+	var checks []operatorsv1alpha1.HealthCheck
+	var invalid []string
+	for _, check := range bundleChecks {
+		if overridden.Has(check.Name) {
+			continue
+		}
+		if err := healthcheck.ValidateBundleCheck(check); err != nil {
+			invalid = append(invalid, err.Error())
+			continue
+		}
+		checks = append(checks, check)
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid health checks declared by the bundle, which can be replaced by checks of the same name in .spec.healthChecks: %s", strings.Join(invalid, "; "))
+	}
+	return append(checks, op.Spec.HealthChecks...), nil
+}
+
+// workloadHealthProblems describes the workloads installed by the named BundleDeployment
+// that are not healthy: Deployments that are not available or whose pods are not all ready,
+// and CustomResourceDefinitions that are not established.
+func (r *OperatorReconciler) workloadHealthProblems(ctx context.Context, bundleDeploymentName string) ([]string, error) {
+	ownedBy := client.MatchingLabels{
+		bundleDeploymentOwnerKindLabel: rukpakv1alpha1.BundleDeploymentKind,
+		bundleDeploymentOwnerNameLabel: bundleDeploymentName,
+	}
+	var problems []string
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.Client.List(ctx, deployments, ownedBy); err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments.Items {
+		if problem := deploymentHealthProblem(&deployment); problem != "" {
+			problems = append(problems, fmt.Sprintf("deployment %s/%s %s", deployment.GetNamespace(), deployment.GetName(), problem))
+		}
+	}
+
+	// the labels of the CRDs tell which ones the BundleDeployment installed, and only those are
+	// read in full to find whether they are established
+	crds := &metav1.PartialObjectMetadataList{}
+	crds.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinitionList"))
+	if err := r.Client.List(ctx, crds, ownedBy); err != nil {
+		return nil, err
+	}
+	for _, item := range crds.Items {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(&item), crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !crdEstablished(crd) {
+			problems = append(problems, fmt.Sprintf("customresourcedefinition %s is not established", crd.GetName()))
+		}
+	}
+	return problems, nil
+}
+
+// deploymentHealthProblem describes why the Deployment is not healthy, or returns an empty
+// string if it is. Pod readiness is read from the status of the Deployment, which tracks the
+// readiness of the pods it manages.
+func deploymentHealthProblem(deployment *appsv1.Deployment) string {
+	if deployment.Status.ObservedGeneration < deployment.GetGeneration() {
+		return "has not observed its latest spec yet"
+	}
+	available := false
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentAvailable {
+			if cond.Status != corev1.ConditionTrue {
+				return fmt.Sprintf("is not available: %s", cond.Message)
+			}
+			available = true
+		}
+	}
+	if !available {
+		return "is not available"
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	if deployment.Status.ReadyReplicas < replicas {
+		return fmt.Sprintf("has %d of %d pods ready", deployment.Status.ReadyReplicas, replicas)
+	}
+	return ""
+}
+
+// crdEstablished returns true if the CustomResourceDefinition is served by the API server.
+func crdEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type == apiextensionsv1.Established {
+			return cond.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// operatorRequestsForWorkload maps an object installed by a BundleDeployment to the Operator
+// the BundleDeployment was created for, so that changes to the health of the workloads
+// installed for an Operator are reflected in its status.
+func operatorRequestsForWorkload(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[bundleDeploymentOwnerKindLabel] != rukpakv1alpha1.BundleDeploymentKind || labels[bundleDeploymentOwnerNameLabel] == "" {
+		return nil
+	}
+	// BundleDeployments are named after the Operator they are created for
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: labels[bundleDeploymentOwnerNameLabel]}}}
+}
@@ -0,0 +1,224 @@
+// Package healthcheck evaluates the custom health checks declared for an Operator, either by
+// the Operator itself or by the bundle it installs.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+const (
+	// defaultHTTPTimeout bounds the HTTP checks made by a Checker that is not given its own client.
+	defaultHTTPTimeout = 5 * time.Second
+
+	// maxDrainedBodyBytes bounds how much of the response of an HTTP check is read. Larger
+	// responses are not drained, at the cost of the connection not being reused.
+	maxDrainedBodyBytes = 64 << 10
+
+	// maxExpressionCost bounds the cost of evaluating an expression, like the per-expression
+	// cost limit of CEL validation rules in CustomResourceDefinitions.
+	maxExpressionCost = 1000000
+
+	// maxCachedPrograms bounds the number of compiled expressions kept around.
+	maxCachedPrograms = 256
+
+	// AggregateToHealthCheckLabel labels the ClusterRoles that grant operator-controller the
+	// permission to get the objects inspected by health checks.
+	AggregateToHealthCheckLabel = "operators.operatorframework.io/aggregate-to-health-check"
+)
+
+// programs caches the compiled expressions, which do not need to be compiled again each time
+// they are validated or evaluated.
+var programs = &programCache{}
+
+// Validate returns an error if the health check is not well-formed: exactly one of condition,
+// expression and httpGet must be specified, condition and expression checks must identify the
+// object they inspect, and expressions must compile.
+func Validate(check operatorsv1alpha1.HealthCheck) error {
+	specified := 0
+	if check.Condition != nil {
+		specified++
+	}
+	if check.Expression != "" {
+		specified++
+	}
+	if check.HTTPGet != nil {
+		specified++
+	}
+	if specified != 1 {
+		return fmt.Errorf("health check %q must specify exactly one of condition, expression and httpGet", check.Name)
+	}
+	if (check.Condition != nil || check.Expression != "") && check.Object == nil {
+		return fmt.Errorf("health check %q must specify the object to check", check.Name)
+	}
+	if check.Expression != "" {
+		if _, err := programs.get(check.Expression); err != nil {
+			return fmt.Errorf("health check %q: %w", check.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateBundleCheck is like Validate, for a health check declared by a bundle. Bundles may not
+// declare httpGet checks: the catalog would otherwise choose the endpoints operator-controller
+// sends requests to from within the cluster.
+func ValidateBundleCheck(check operatorsv1alpha1.HealthCheck) error {
+	if check.HTTPGet != nil {
+		return fmt.Errorf("health check %q declared by the bundle must not specify httpGet", check.Name)
+	}
+	return Validate(check)
+}
+
+// Checker evaluates health checks.
+type Checker struct {
+	// Client reads the objects inspected by condition and expression checks.
+	Client client.Reader
+	// HTTPClient makes the requests of httpGet checks. If nil, a client with a short timeout is used.
+	HTTPClient *http.Client
+}
+
+// Check evaluates the health check. It returns an error describing why the check failed, or nil
+// if the check passed.
+func (c *Checker) Check(ctx context.Context, check operatorsv1alpha1.HealthCheck) error {
+	if err := Validate(check); err != nil {
+		return err
+	}
+	if check.HTTPGet != nil {
+		return c.checkHTTPGet(ctx, check.HTTPGet)
+	}
+
+	obj, err := c.getObject(ctx, check.Object)
+	if err != nil {
+		return err
+	}
+	if check.Condition != nil {
+		return checkCondition(obj, check.Condition)
+	}
+	return checkExpression(obj, check.Expression)
+}
+
+func (c *Checker) getObject(ctx context.Context, ref *operatorsv1alpha1.HealthCheckObject) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, fmt.Errorf("not allowed to get %s %q: grant operator-controller the permission in a ClusterRole labeled %s=true", ref.Kind, ref.Name, AggregateToHealthCheckLabel)
+		}
+		return nil, fmt.Errorf("failed to get %s %q: %w", ref.Kind, ref.Name, err)
+	}
+	return obj, nil
+}
+
+func checkCondition(obj *unstructured.Unstructured, expected *operatorsv1alpha1.ConditionHealthCheck) error {
+	expectedStatus := expected.Status
+	if expectedStatus == "" {
+		expectedStatus = metav1.ConditionTrue
+	}
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return fmt.Errorf("failed to read the conditions of %s %q: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	for _, rawCondition := range conditions {
+		condition, ok := rawCondition.(map[string]interface{})
+		if !ok || condition["type"] != expected.Type {
+			continue
+		}
+		if status := condition["status"]; status != string(expectedStatus) {
+			return fmt.Errorf("condition %q of %s %q is %v, expected %s", expected.Type, obj.GetKind(), obj.GetName(), status, expectedStatus)
+		}
+		return nil
+	}
+	return fmt.Errorf("condition %q of %s %q is not set", expected.Type, obj.GetKind(), obj.GetName())
+}
+
+func checkExpression(obj *unstructured.Unstructured, expression string) error {
+	program, err := programs.get(expression)
+	if err != nil {
+		return err
+	}
+	out, _, err := program.Eval(map[string]interface{}{"self": obj.Object})
+	if err != nil {
+		return fmt.Errorf("failed to evaluate expression %q against %s %q: %w", expression, obj.GetKind(), obj.GetName(), err)
+	}
+	if passed, ok := out.Value().(bool); !ok || !passed {
+		return fmt.Errorf("expression %q is not true for %s %q", expression, obj.GetKind(), obj.GetName())
+	}
+	return nil
+}
+
+// compile compiles a CEL expression in which the checked object is available as "self".
+func compile(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(cel.Variable("self", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expression, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("invalid expression %q: must evaluate to a bool, not %s", expression, ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(maxExpressionCost))
+}
+
+// programCache holds compiled expressions. Once full, it is emptied: expressions come from the
+// specs of Operators and from bundles, so there are few distinct ones at any time.
+type programCache struct {
+	mu       sync.Mutex
+	programs map[string]cel.Program
+}
+
+// get returns the compiled expression, compiling it if it is not cached yet.
+func (c *programCache) get(expression string) (cel.Program, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if program, ok := c.programs[expression]; ok {
+		return program, nil
+	}
+	program, err := compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	if c.programs == nil || len(c.programs) >= maxCachedPrograms {
+		c.programs = map[string]cel.Program{}
+	}
+	c.programs[expression] = program
+	return program, nil
+}
+
+func (c *Checker) checkHTTPGet(ctx context.Context, check *operatorsv1alpha1.HTTPGetHealthCheck) error {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body, so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainedBodyBytes))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET %s responded with %s", check.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package healthcheck_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/healthcheck"
+)
+
+var _ = Describe("HealthCheck", func() {
+	var object = &operatorsv1alpha1.HealthCheckObject{
+		APIVersion: "example.com/v1",
+		Kind:       "Widget",
+		Name:       "widget",
+		Namespace:  "default",
+	}
+
+	Describe("Validate", func() {
+		It("accepts a well-formed health check", func() {
+			Expect(healthcheck.Validate(operatorsv1alpha1.HealthCheck{
+				Name:       "ready",
+				Object:     object,
+				Expression: `self.status.phase == "Ready"`,
+			})).To(Succeed())
+		})
+		It("rejects a health check that does not check anything", func() {
+			err := healthcheck.Validate(operatorsv1alpha1.HealthCheck{Name: "empty", Object: object})
+			Expect(err).To(MatchError(`health check "empty" must specify exactly one of condition, expression and httpGet`))
+		})
+		It("rejects a health check that checks more than one thing", func() {
+			err := healthcheck.Validate(operatorsv1alpha1.HealthCheck{
+				Name:       "both",
+				Object:     object,
+				Condition:  &operatorsv1alpha1.ConditionHealthCheck{Type: "Ready"},
+				Expression: "true",
+			})
+			Expect(err).To(MatchError(`health check "both" must specify exactly one of condition, expression and httpGet`))
+		})
+		It("rejects a condition check without an object", func() {
+			err := healthcheck.Validate(operatorsv1alpha1.HealthCheck{
+				Name:      "ready",
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Ready"},
+			})
+			Expect(err).To(MatchError(`health check "ready" must specify the object to check`))
+		})
+		It("rejects an expression that does not compile", func() {
+			err := healthcheck.Validate(operatorsv1alpha1.HealthCheck{
+				Name:       "ready",
+				Object:     object,
+				Expression: "self.status.phase ==",
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(`health check "ready": invalid expression "self.status.phase =="`))
+		})
+		It("rejects an expression that does not evaluate to a bool", func() {
+			err := healthcheck.Validate(operatorsv1alpha1.HealthCheck{
+				Name:       "ready",
+				Object:     object,
+				Expression: `"Ready"`,
+			})
+			Expect(err).To(MatchError(`health check "ready": invalid expression "\"Ready\"": must evaluate to a bool, not string`))
+		})
+	})
+
+	Describe("ValidateBundleCheck", func() {
+		It("accepts a well-formed health check", func() {
+			Expect(healthcheck.ValidateBundleCheck(operatorsv1alpha1.HealthCheck{
+				Name:      "ready",
+				Object:    object,
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Ready"},
+			})).To(Succeed())
+		})
+		It("rejects an http health check", func() {
+			err := healthcheck.ValidateBundleCheck(operatorsv1alpha1.HealthCheck{
+				Name:    "healthz",
+				HTTPGet: &operatorsv1alpha1.HTTPGetHealthCheck{URL: "http://169.254.169.254/latest/meta-data"},
+			})
+			Expect(err).To(MatchError(`health check "healthz" declared by the bundle must not specify httpGet`))
+		})
+	})
+
+	Describe("Check", func() {
+		var checker *healthcheck.Checker
+		BeforeEach(func() {
+			widget := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata": map[string]interface{}{
+					"name":      "widget",
+					"namespace": "default",
+				},
+				"status": map[string]interface{}{
+					"phase": "Ready",
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True"},
+						map[string]interface{}{"type": "Degraded", "status": "False"},
+					},
+				},
+			}}
+			checker = &healthcheck.Checker{
+				Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(widget).Build(),
+			}
+		})
+
+		It("passes when the condition has the expected status", func() {
+			Expect(checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:      "ready",
+				Object:    object,
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Ready"},
+			})).To(Succeed())
+			Expect(checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:      "not-degraded",
+				Object:    object,
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Degraded", Status: metav1.ConditionFalse},
+			})).To(Succeed())
+		})
+		It("fails when the condition does not have the expected status", func() {
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:      "degraded",
+				Object:    object,
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Degraded"},
+			})
+			Expect(err).To(MatchError(`condition "Degraded" of Widget "widget" is False, expected True`))
+		})
+		It("fails when the condition is not set", func() {
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:      "available",
+				Object:    object,
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Available"},
+			})
+			Expect(err).To(MatchError(`condition "Available" of Widget "widget" is not set`))
+		})
+		It("fails when the object does not exist", func() {
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name: "ready",
+				Object: &operatorsv1alpha1.HealthCheckObject{
+					APIVersion: "example.com/v1",
+					Kind:       "Widget",
+					Name:       "missing",
+					Namespace:  "default",
+				},
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Ready"},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(`failed to get Widget "missing"`))
+		})
+		It("passes when the expression is true", func() {
+			Expect(checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:       "ready",
+				Object:     object,
+				Expression: `self.status.phase == "Ready"`,
+			})).To(Succeed())
+		})
+		It("fails when the expression is false", func() {
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:       "failed",
+				Object:     object,
+				Expression: `self.status.phase == "Failed"`,
+			})
+			Expect(err).To(MatchError(`expression "self.status.phase == \"Failed\"" is not true for Widget "widget"`))
+		})
+		It("fails when the expression cannot be evaluated", func() {
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:       "replicas",
+				Object:     object,
+				Expression: `self.status.replicas > 0`,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(`failed to evaluate expression "self.status.replicas > 0" against Widget "widget"`))
+		})
+		It("fails when the expression is too expensive to evaluate", func() {
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:       "expensive",
+				Object:     object,
+				Expression: `[1,2,3,4,5,6,7,8,9,10].all(a, [1,2,3,4,5,6,7,8,9,10].all(b, [1,2,3,4,5,6,7,8,9,10].all(c, [1,2,3,4,5,6,7,8,9,10].all(d, [1,2,3,4,5,6,7,8,9,10].all(e, [1,2,3,4,5,6,7,8,9,10].all(f, a+b+c+d+e+f > 0))))))`,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cost limit exceeded"))
+		})
+		It("fails when it is not allowed to get the object", func() {
+			checker.Client = forbiddenReader{}
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:      "ready",
+				Object:    object,
+				Condition: &operatorsv1alpha1.ConditionHealthCheck{Type: "Ready"},
+			})
+			Expect(err).To(MatchError(`not allowed to get Widget "widget": grant operator-controller the permission in a ClusterRole labeled operators.operatorframework.io/aggregate-to-health-check=true`))
+		})
+		It("passes when the endpoint responds successfully", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			Expect(checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:    "healthz",
+				HTTPGet: &operatorsv1alpha1.HTTPGetHealthCheck{URL: server.URL + "/healthz"},
+			})).To(Succeed())
+		})
+		It("fails when the endpoint responds with an error", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			err := checker.Check(context.Background(), operatorsv1alpha1.HealthCheck{
+				Name:    "healthz",
+				HTTPGet: &operatorsv1alpha1.HTTPGetHealthCheck{URL: server.URL + "/healthz"},
+			})
+			Expect(err).To(MatchError("GET " + server.URL + "/healthz responded with 503 Service Unavailable"))
+		})
+	})
+})
+
+// forbiddenReader is a client that is not allowed to read anything.
+type forbiddenReader struct {
+	client.Reader
+}
+
+func (forbiddenReader) Get(_ context.Context, key client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	return apierrors.NewForbidden(schema.GroupResource{Group: "example.com", Resource: "widgets"}, key.Name, errors.New("not today"))
+}
@@ -0,0 +1,126 @@
+// Package patches applies the patches declared by an Operator to the objects of its bundle.
+package patches
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// Validate returns an error if the patch cannot be parsed as a patch of its type.
+func Validate(patch operatorsv1alpha1.ManifestPatch) error {
+	data, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return err
+	}
+	switch patch.Type {
+	case operatorsv1alpha1.PatchTypeJSON6902:
+		_, err = jsonpatch.DecodePatch(data)
+		return err
+	case operatorsv1alpha1.PatchTypeStrategicMerge, "":
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("a strategic merge patch must be an object: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown patch type %q", patch.Type)
+	}
+}
+
+// Matches returns true if the object is selected by the target.
+func Matches(target operatorsv1alpha1.PatchTarget, obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == target.Group &&
+		(target.Version == "" || gvk.Version == target.Version) &&
+		gvk.Kind == target.Kind &&
+		(target.Name == "" || obj.GetName() == target.Name) &&
+		(target.Namespace == "" || obj.GetNamespace() == target.Namespace)
+}
+
+// Describe describes the target of a patch, e.g. "Deployment.apps foo in namespace bar".
+func Describe(target operatorsv1alpha1.PatchTarget) string {
+	description := target.Kind
+	if target.Group != "" {
+		description = fmt.Sprintf("%s.%s", target.Kind, target.Group)
+	}
+	if target.Name != "" {
+		description = fmt.Sprintf("%s %s", description, target.Name)
+	}
+	if target.Namespace != "" {
+		description = fmt.Sprintf("%s in namespace %s", description, target.Namespace)
+	}
+	return description
+}
+
+// Apply applies each of the patches to the objects they match, in order, and returns the patched
+// objects along with the indexes of the patches that matched no object. The given objects are not
+// modified.
+func Apply(objects []unstructured.Unstructured, patches []operatorsv1alpha1.ManifestPatch) ([]unstructured.Unstructured, []int, error) {
+	patched := make([]unstructured.Unstructured, 0, len(objects))
+	for i := range objects {
+		patched = append(patched, *objects[i].DeepCopy())
+	}
+	var unmatched []int
+	for i, patch := range patches {
+		matched := false
+		for j := range patched {
+			obj := &patched[j]
+			if !Matches(patch.Target, obj) {
+				continue
+			}
+			matched = true
+			if err := apply(obj, patch); err != nil {
+				return nil, nil, fmt.Errorf("patches[%d] failed to apply to %s %q: %w", i, obj.GetKind(), obj.GetName(), err)
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, i)
+		}
+	}
+	return patched, unmatched, nil
+}
+
+// apply applies the patch to the object in place.
+func apply(obj *unstructured.Unstructured, patch operatorsv1alpha1.ManifestPatch) error {
+	data, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return err
+	}
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var result []byte
+	switch patch.Type {
+	case operatorsv1alpha1.PatchTypeJSON6902:
+		operations, err := jsonpatch.DecodePatch(data)
+		if err != nil {
+			return err
+		}
+		if result, err = operations.Apply(original); err != nil {
+			return err
+		}
+	default:
+		// only the built-in kinds have patch strategies
+		typed, err := scheme.Scheme.New(obj.GroupVersionKind())
+		if err != nil {
+			if result, err = jsonpatch.MergePatch(original, data); err != nil {
+				return err
+			}
+			break
+		}
+		if result, err = strategicpatch.StrategicMergePatch(original, data, typed); err != nil {
+			return err
+		}
+	}
+	return obj.UnmarshalJSON(result)
+}
@@ -0,0 +1,13 @@
+package patches_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPatches(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Patches Suite")
+}
@@ -0,0 +1,114 @@
+package patches_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/patches"
+)
+
+var _ = Describe("Patches", func() {
+	var objects []unstructured.Unstructured
+	BeforeEach(func() {
+		objects = []unstructured.Unstructured{
+			{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "foo-operator", "namespace": "foo-system"},
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "manager", "image": "foo:v1"},
+						map[string]interface{}{"name": "proxy", "image": "proxy:v1"},
+					},
+				}}},
+			}},
+			{Object: map[string]interface{}{
+				"apiVersion": "admissionregistration.k8s.io/v1",
+				"kind":       "ValidatingWebhookConfiguration",
+				"metadata":   map[string]interface{}{"name": "foo-webhook"},
+				"webhooks": []interface{}{
+					map[string]interface{}{"name": "foos.foo.io", "failurePolicy": "Fail"},
+				},
+			}},
+			{Object: map[string]interface{}{
+				"apiVersion": "foo.io/v1",
+				"kind":       "Foo",
+				"metadata":   map[string]interface{}{"name": "default"},
+				"spec":       map[string]interface{}{"replicas": int64(1), "mode": "fast"},
+			}},
+		}
+	})
+
+	It("validates patches", func() {
+		Expect(patches.Validate(operatorsv1alpha1.ManifestPatch{Patch: "metadata:\n  labels:\n    foo: bar\n"})).To(Succeed())
+		Expect(patches.Validate(operatorsv1alpha1.ManifestPatch{Patch: "- foo"})).To(MatchError(ContainSubstring("a strategic merge patch must be an object")))
+		Expect(patches.Validate(operatorsv1alpha1.ManifestPatch{Type: operatorsv1alpha1.PatchTypeJSON6902, Patch: `[{"op":"remove","path":"/spec"}]`})).To(Succeed())
+		Expect(patches.Validate(operatorsv1alpha1.ManifestPatch{Type: operatorsv1alpha1.PatchTypeJSON6902, Patch: `{"op":"remove"}`})).NotTo(Succeed())
+	})
+
+	It("merges strategic merge patches following the patch strategies of built-in kinds", func() {
+		patched, unmatched, err := patches.Apply(objects, []operatorsv1alpha1.ManifestPatch{{
+			Target: operatorsv1alpha1.PatchTarget{Group: "apps", Kind: "Deployment", Name: "foo-operator"},
+			Patch:  "spec:\n  template:\n    spec:\n      containers:\n      - name: manager\n        image: foo:v2\n",
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unmatched).To(BeEmpty())
+		containers, _, _ := unstructured.NestedSlice(patched[0].Object, "spec", "template", "spec", "containers")
+		Expect(containers).To(Equal([]interface{}{
+			map[string]interface{}{"name": "manager", "image": "foo:v2"},
+			map[string]interface{}{"name": "proxy", "image": "proxy:v1"},
+		}))
+
+		By("leaving the given objects untouched")
+		containers, _, _ = unstructured.NestedSlice(objects[0].Object, "spec", "template", "spec", "containers")
+		Expect(containers[0]).To(HaveKeyWithValue("image", "foo:v1"))
+	})
+
+	It("merges strategic merge patches of other kinds as JSON merge patches", func() {
+		patched, unmatched, err := patches.Apply(objects, []operatorsv1alpha1.ManifestPatch{{
+			Target: operatorsv1alpha1.PatchTarget{Group: "foo.io", Version: "v1", Kind: "Foo"},
+			Patch:  `{"spec":{"mode":null,"replicas":3}}`,
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unmatched).To(BeEmpty())
+		Expect(patched[2].Object["spec"]).To(Equal(map[string]interface{}{"replicas": int64(3)}))
+	})
+
+	It("applies JSON6902 patches", func() {
+		patched, unmatched, err := patches.Apply(objects, []operatorsv1alpha1.ManifestPatch{{
+			Target: operatorsv1alpha1.PatchTarget{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration", Name: "foo-webhook"},
+			Type:   operatorsv1alpha1.PatchTypeJSON6902,
+			Patch:  `[{"op":"replace","path":"/webhooks/0/failurePolicy","value":"Ignore"}]`,
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unmatched).To(BeEmpty())
+		webhooks, _, _ := unstructured.NestedSlice(patched[1].Object, "webhooks")
+		Expect(webhooks[0]).To(HaveKeyWithValue("failurePolicy", "Ignore"))
+	})
+
+	It("reports the patches that match no object", func() {
+		_, unmatched, err := patches.Apply(objects, []operatorsv1alpha1.ManifestPatch{
+			{Target: operatorsv1alpha1.PatchTarget{Group: "apps", Kind: "Deployment"}, Patch: "metadata:\n  labels:\n    foo: bar\n"},
+			{Target: operatorsv1alpha1.PatchTarget{Group: "apps", Kind: "Deployment", Namespace: "default"}, Patch: "{}"},
+			{Target: operatorsv1alpha1.PatchTarget{Kind: "Service", Name: "foo"}, Patch: "{}"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unmatched).To(Equal([]int{1, 2}))
+	})
+
+	It("fails when a patch does not apply to an object it matches", func() {
+		_, _, err := patches.Apply(objects, []operatorsv1alpha1.ManifestPatch{{
+			Target: operatorsv1alpha1.PatchTarget{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+			Type:   operatorsv1alpha1.PatchTypeJSON6902,
+			Patch:  `[{"op":"replace","path":"/webhooks/3/failurePolicy","value":"Ignore"}]`,
+		}})
+		Expect(err).To(MatchError(ContainSubstring(`patches[0] failed to apply to ValidatingWebhookConfiguration "foo-webhook"`)))
+	})
+
+	It("describes the targets of patches", func() {
+		Expect(patches.Describe(operatorsv1alpha1.PatchTarget{Group: "apps", Kind: "Deployment", Namespace: "foo-system", Name: "foo-operator"})).To(Equal("Deployment.apps foo-operator in namespace foo-system"))
+		Expect(patches.Describe(operatorsv1alpha1.PatchTarget{Kind: "Service"})).To(Equal("Service"))
+	})
+})
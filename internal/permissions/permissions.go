@@ -0,0 +1,255 @@
+// Package permissions reads the RBAC permissions a bundle grants to the operator it installs, and
+// compares the permissions granted by different bundles.
+package permissions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Permissions are the RBAC rules granted by a bundle.
+type Permissions struct {
+	// ClusterRules are the rules granted cluster-wide.
+	ClusterRules []rbacv1.PolicyRule
+	// NamespaceRules are the rules granted in each namespace.
+	NamespaceRules map[string][]rbacv1.PolicyRule
+}
+
+// Permission is a single request the rules of a bundle allow, e.g. to create deployments in a namespace.
+type Permission struct {
+	// Namespace is the namespace the permission is granted in, or empty if it is granted cluster-wide.
+	Namespace string
+	// Verb is the verb of the request.
+	Verb string
+	// APIGroup and Resource identify the resource of the request, unless it is a non-resource request.
+	APIGroup string
+	Resource string
+	// ResourceName is the name of the object the permission is restricted to, if any.
+	ResourceName string
+	// NonResourceURL is the path of a non-resource request.
+	NonResourceURL string
+}
+
+// String describes the permission, e.g. "create deployments.apps in namespace foo".
+func (p Permission) String() string {
+	var target string
+	switch {
+	case p.NonResourceURL != "":
+		target = p.NonResourceURL
+	case p.APIGroup == "":
+		target = p.Resource
+	default:
+		target = fmt.Sprintf("%s.%s", p.Resource, p.APIGroup)
+	}
+	if p.ResourceName != "" {
+		target = fmt.Sprintf("%s named %s", target, p.ResourceName)
+	}
+	if p.Namespace == "" {
+		return fmt.Sprintf("%s %s", p.Verb, target)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", p.Verb, target, p.Namespace)
+}
+
+// ForBundle returns the permissions granted by the given objects of a bundle: the permissions the
+// ClusterServiceVersion of a registry bundle grants its operator, and the rules of the ClusterRoles
+// and Roles shipped with the bundle.
+// The namespaced permissions of a ClusterServiceVersion are granted in the install namespace and in
+// each of the watched namespaces, or cluster-wide if the operator watches all namespaces.
+func ForBundle(objects []unstructured.Unstructured, installNamespace string, watchNamespaces []string) (*Permissions, error) {
+	permissions := &Permissions{NamespaceRules: map[string][]rbacv1.PolicyRule{}}
+	operatorNamespaces := sets.NewString(watchNamespaces...)
+	if operatorNamespaces.Len() > 0 {
+		operatorNamespaces.Insert(installNamespace)
+	}
+
+	for i := range objects {
+		obj := &objects[i]
+		switch obj.GroupVersionKind().GroupKind() {
+		case rbacv1.SchemeGroupVersion.WithKind("ClusterRole").GroupKind():
+			rules, err := policyRules(obj.Object, "rules")
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the rules of ClusterRole %q: %w", obj.GetName(), err)
+			}
+			permissions.ClusterRules = append(permissions.ClusterRules, rules...)
+		case rbacv1.SchemeGroupVersion.WithKind("Role").GroupKind():
+			rules, err := policyRules(obj.Object, "rules")
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the rules of Role %q: %w", obj.GetName(), err)
+			}
+			namespace := obj.GetNamespace()
+			if namespace == "" {
+				namespace = installNamespace
+			}
+			permissions.NamespaceRules[namespace] = append(permissions.NamespaceRules[namespace], rules...)
+		default:
+			if obj.GetKind() != "ClusterServiceVersion" {
+				continue
+			}
+			clusterRules, err := csvPermissionRules(obj, "clusterPermissions")
+			if err != nil {
+				return nil, err
+			}
+			permissions.ClusterRules = append(permissions.ClusterRules, clusterRules...)
+			namespaceRules, err := csvPermissionRules(obj, "permissions")
+			if err != nil {
+				return nil, err
+			}
+			if operatorNamespaces.Len() == 0 {
+				permissions.ClusterRules = append(permissions.ClusterRules, namespaceRules...)
+				continue
+			}
+			for _, namespace := range operatorNamespaces.List() {
+				permissions.NamespaceRules[namespace] = append(permissions.NamespaceRules[namespace], namespaceRules...)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// csvPermissionRules returns the rules of the given permissions of the install strategy of a
+// ClusterServiceVersion.
+func csvPermissionRules(csv *unstructured.Unstructured, field string) ([]rbacv1.PolicyRule, error) {
+	entries, _, err := unstructured.NestedSlice(csv.Object, "spec", "install", "spec", field)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the %s of ClusterServiceVersion %q: %w", field, csv.GetName(), err)
+	}
+	var rules []rbacv1.PolicyRule
+	for _, entry := range entries {
+		entryRules, err := policyRules(entry, "rules")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s of ClusterServiceVersion %q: %w", field, csv.GetName(), err)
+		}
+		rules = append(rules, entryRules...)
+	}
+	return rules, nil
+}
+
+// policyRules converts the rules found in the given field of an object.
+func policyRules(obj interface{}, field string) ([]rbacv1.PolicyRule, error) {
+	fields, ok := obj.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %T", obj)
+	}
+	entries, _, err := unstructured.NestedSlice(fields, field)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]rbacv1.PolicyRule, 0, len(entries))
+	for _, entry := range entries {
+		entryFields, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a rule, got %T", entry)
+		}
+		rule := rbacv1.PolicyRule{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(entryFields, &rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Namespaces returns the namespaces rules are granted in, sorted by name.
+func (p *Permissions) Namespaces() []string {
+	namespaces := make([]string, 0, len(p.NamespaceRules))
+	for namespace := range p.NamespaceRules {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// List returns each of the permissions granted by the rules, sorted by their description.
+func (p *Permissions) List() []Permission {
+	seen := map[Permission]struct{}{}
+	var list []Permission
+	add := func(namespace string, rules []rbacv1.PolicyRule) {
+		for _, rule := range rules {
+			for _, permission := range expand(namespace, rule) {
+				if _, ok := seen[permission]; ok {
+					continue
+				}
+				seen[permission] = struct{}{}
+				list = append(list, permission)
+			}
+		}
+	}
+	add("", p.ClusterRules)
+	for _, namespace := range p.Namespaces() {
+		add(namespace, p.NamespaceRules[namespace])
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].String() < list[j].String()
+	})
+	return list
+}
+
+// expand returns each of the permissions granted by a rule in the given namespace.
+func expand(namespace string, rule rbacv1.PolicyRule) []Permission {
+	var permissions []Permission
+	for _, verb := range rule.Verbs {
+		for _, url := range rule.NonResourceURLs {
+			// non-resource URLs are only granted cluster-wide
+			if namespace == "" {
+				permissions = append(permissions, Permission{Verb: verb, NonResourceURL: url})
+			}
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				if len(rule.ResourceNames) == 0 {
+					permissions = append(permissions, Permission{Namespace: namespace, Verb: verb, APIGroup: group, Resource: resource})
+					continue
+				}
+				for _, name := range rule.ResourceNames {
+					permissions = append(permissions, Permission{Namespace: namespace, Verb: verb, APIGroup: group, Resource: resource, ResourceName: name})
+				}
+			}
+		}
+	}
+	return permissions
+}
+
+// Diff returns the descriptions of the permissions granted by to that are not granted by from, and of
+// those granted by from that are no longer granted by to.
+func Diff(from, to *Permissions) ([]string, []string) {
+	fromPermissions, toPermissions := describe(from.List()), describe(to.List())
+	return toPermissions.Difference(fromPermissions).List(), fromPermissions.Difference(toPermissions).List()
+}
+
+func describe(permissions []Permission) sets.String {
+	descriptions := sets.NewString()
+	for _, permission := range permissions {
+		descriptions.Insert(permission.String())
+	}
+	return descriptions
+}
+
+// Summary summarizes the rules, e.g. "2 rules cluster-wide and 1 rule in namespace foo".
+func (p *Permissions) Summary() string {
+	namespaceRules := 0
+	for _, rules := range p.NamespaceRules {
+		namespaceRules += len(rules)
+	}
+	summary := pluralize(len(p.ClusterRules), "rule") + " cluster-wide"
+	if namespaces := p.Namespaces(); len(namespaces) > 0 {
+		summary = fmt.Sprintf("%s and %s in %s %s", summary, pluralize(namespaceRules, "rule"), pluralizeWord(len(namespaces), "namespace"), strings.Join(namespaces, ", "))
+	}
+	return summary
+}
+
+func pluralize(n int, word string) string {
+	return fmt.Sprintf("%d %s", n, pluralizeWord(n, word))
+}
+
+func pluralizeWord(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,116 @@
+package permissions_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/operator-framework/operator-controller/internal/permissions"
+)
+
+var _ = Describe("Permissions", func() {
+	var objects []unstructured.Unstructured
+	BeforeEach(func() {
+		objects = []unstructured.Unstructured{
+			{Object: map[string]interface{}{
+				"apiVersion": "operators.coreos.com/v1alpha1",
+				"kind":       "ClusterServiceVersion",
+				"metadata":   map[string]interface{}{"name": "foo.v1.0.0"},
+				"spec": map[string]interface{}{"install": map[string]interface{}{"spec": map[string]interface{}{
+					"permissions": []interface{}{map[string]interface{}{
+						"serviceAccountName": "foo",
+						"rules": []interface{}{map[string]interface{}{
+							"apiGroups": []interface{}{""},
+							"resources": []interface{}{"configmaps"},
+							"verbs":     []interface{}{"get", "list"},
+						}},
+					}},
+					"clusterPermissions": []interface{}{map[string]interface{}{
+						"serviceAccountName": "foo",
+						"rules": []interface{}{
+							map[string]interface{}{
+								"apiGroups": []interface{}{"apps"},
+								"resources": []interface{}{"deployments"},
+								"verbs":     []interface{}{"create"},
+							},
+							map[string]interface{}{
+								"nonResourceURLs": []interface{}{"/metrics"},
+								"verbs":           []interface{}{"get"},
+							},
+						},
+					}},
+				}}},
+			}},
+			{Object: map[string]interface{}{
+				"apiVersion": "rbac.authorization.k8s.io/v1",
+				"kind":       "Role",
+				"metadata":   map[string]interface{}{"name": "foo-leases"},
+				"rules": []interface{}{map[string]interface{}{
+					"apiGroups":     []interface{}{"coordination.k8s.io"},
+					"resources":     []interface{}{"leases"},
+					"resourceNames": []interface{}{"foo-lock"},
+					"verbs":         []interface{}{"update"},
+				}},
+			}},
+		}
+	})
+
+	It("grants the namespaced permissions of an operator watching all namespaces cluster-wide", func() {
+		granted, err := permissions.ForBundle(objects, "foo-system", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(granted.ClusterRules).To(HaveLen(3))
+		Expect(granted.Namespaces()).To(Equal([]string{"foo-system"}))
+		Expect(describe(granted.List())).To(Equal([]string{
+			"create deployments.apps",
+			"get /metrics",
+			"get configmaps",
+			"list configmaps",
+			"update leases.coordination.k8s.io named foo-lock in namespace foo-system",
+		}))
+		Expect(granted.Summary()).To(Equal("3 rules cluster-wide and 1 rule in namespace foo-system"))
+	})
+
+	It("grants the namespaced permissions of an operator in the install and watched namespaces", func() {
+		granted, err := permissions.ForBundle(objects, "foo-system", []string{"bar"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(granted.ClusterRules).To(HaveLen(2))
+		Expect(granted.Namespaces()).To(Equal([]string{"bar", "foo-system"}))
+		Expect(granted.NamespaceRules["bar"]).To(Equal([]rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"get", "list"},
+		}}))
+		Expect(granted.Summary()).To(Equal("2 rules cluster-wide and 3 rules in namespaces bar, foo-system"))
+	})
+
+	It("lists the permissions added and removed by another bundle", func() {
+		from, err := permissions.ForBundle(objects, "foo-system", nil)
+		Expect(err).NotTo(HaveOccurred())
+		to := &permissions.Permissions{ClusterRules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"create", "delete"}},
+			{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+		}}
+		added, removed := permissions.Diff(from, to)
+		Expect(added).To(Equal([]string{"delete deployments.apps"}))
+		Expect(removed).To(Equal([]string{
+			"get configmaps",
+			"list configmaps",
+			"update leases.coordination.k8s.io named foo-lock in namespace foo-system",
+		}))
+	})
+
+	It("fails on malformed rules", func() {
+		Expect(unstructured.SetNestedField(objects[1].Object, "everything", "rules")).To(Succeed())
+		_, err := permissions.ForBundle(objects, "foo-system", nil)
+		Expect(err).To(MatchError(ContainSubstring(`failed to read the rules of Role "foo-leases"`)))
+	})
+})
+
+func describe(list []permissions.Permission) []string {
+	descriptions := make([]string, 0, len(list))
+	for _, permission := range list {
+		descriptions = append(descriptions, permission.String())
+	}
+	return descriptions
+}
@@ -0,0 +1,151 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxReportedInvalidResources is the number of invalid custom resources described per
+	// version of a CRD, so that the reported problems remain readable.
+	maxReportedInvalidResources = 3
+
+	// customResourcesPageSize is the number of custom resources listed at once.
+	customResourcesPageSize = 500
+)
+
+// CRDUpgradeSafetyCheck checks that the CRDs of a bundle can safely replace the CRDs on the
+// cluster: they must keep every version that objects may still be stored at, and their schemas
+// must accept the existing custom resources. The custom resources operator-controller is not allowed
+// to list are not checked, which the check reports as a warning.
+type CRDUpgradeSafetyCheck struct {
+	Client client.Reader
+}
+
+func (c *CRDUpgradeSafetyCheck) Name() string {
+	return "CRDUpgradeSafety"
+}
+
+func (c *CRDUpgradeSafetyCheck) Check(ctx context.Context, bundle *Bundle) error {
+	var problems, warnings []string
+	for i := range bundle.CustomResourceDefinitions {
+		newCRD := &bundle.CustomResourceDefinitions[i]
+		existingCRD := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Client.Get(ctx, types.NamespacedName{Name: newCRD.GetName()}, existingCRD); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		problems = append(problems, removedStoredVersions(existingCRD, newCRD)...)
+		invalidResources, err := c.invalidCustomResources(ctx, existingCRD, newCRD)
+		if apierrors.IsForbidden(err) {
+			warnings = append(warnings, fmt.Sprintf("the existing %s were not checked, as operator-controller is not allowed to list them: grant it the permission in a ClusterRole labeled %s=true", existingCRD.Spec.Names.Plural, AggregateToPreflightLabel))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		problems = append(problems, invalidResources...)
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	if len(warnings) > 0 {
+		return &WarningError{Err: errors.New(strings.Join(warnings, "; "))}
+	}
+	return nil
+}
+
+// removedStoredVersions describes the versions of the existing CRD that objects may still be
+// stored at, but that the new CRD removes. The objects stored at these versions could no longer
+// be read.
+func removedStoredVersions(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) []string {
+	newVersions := sets.NewString()
+	for _, version := range newCRD.Spec.Versions {
+		newVersions.Insert(version.Name)
+	}
+	var problems []string
+	for _, storedVersion := range existingCRD.Status.StoredVersions {
+		if !newVersions.Has(storedVersion) {
+			problems = append(problems, fmt.Sprintf("CustomResourceDefinition %s removes version %s, which is still listed in status.storedVersions", newCRD.GetName(), storedVersion))
+		}
+	}
+	return problems
+}
+
+// invalidCustomResources describes the existing custom resources that the schemas of the new CRD
+// would not accept, for each version that is served by both the existing and the new CRD. It returns
+// the error of the API server if the custom resources cannot be listed.
+func (c *CRDUpgradeSafetyCheck) invalidCustomResources(ctx context.Context, existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) ([]string, error) {
+	servedVersions := sets.NewString()
+	for _, version := range existingCRD.Spec.Versions {
+		if version.Served {
+			servedVersions.Insert(version.Name)
+		}
+	}
+
+	var problems []string
+	for _, version := range newCRD.Spec.Versions {
+		if !version.Served || !servedVersions.Has(version.Name) || version.Schema == nil {
+			continue
+		}
+		internalValidation := &apiextensions.CustomResourceValidation{}
+		if err := apiextensionsv1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(version.Schema, internalValidation, nil); err != nil {
+			return nil, fmt.Errorf("failed to convert the schema of version %s of CustomResourceDefinition %s: %w", version.Name, newCRD.GetName(), err)
+		}
+		validator, _, err := validation.NewSchemaValidator(internalValidation)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema of version %s of CustomResourceDefinition %s: %w", version.Name, newCRD.GetName(), err)
+		}
+
+		var invalid []string
+		customResources := &unstructured.UnstructuredList{}
+		customResources.SetAPIVersion(fmt.Sprintf("%s/%s", existingCRD.Spec.Group, version.Name))
+		customResources.SetKind(existingCRD.Spec.Names.ListKind)
+		for {
+			if err := c.Client.List(ctx, customResources, client.Limit(customResourcesPageSize), client.Continue(customResources.GetContinue())); err != nil {
+				if apierrors.IsForbidden(err) {
+					return nil, err
+				}
+				return nil, fmt.Errorf("failed to list the existing %s: %w", existingCRD.Spec.Names.Plural, err)
+			}
+			for _, cr := range customResources.Items {
+				if errs := validation.ValidateCustomResource(nil, cr.UnstructuredContent(), validator); len(errs) > 0 {
+					invalid = append(invalid, fmt.Sprintf("%s: %v", client.ObjectKeyFromObject(&cr), errs.ToAggregate()))
+				}
+			}
+			if customResources.GetContinue() == "" {
+				break
+			}
+		}
+		if len(invalid) == 0 {
+			continue
+		}
+		message := fmt.Sprintf("version %s of CustomResourceDefinition %s does not accept %d existing %s: %s", version.Name, newCRD.GetName(), len(invalid), existingCRD.Spec.Names.Plural, strings.Join(firstN(invalid, maxReportedInvalidResources), ", "))
+		if len(invalid) > maxReportedInvalidResources {
+			message += fmt.Sprintf(" and %d more", len(invalid)-maxReportedInvalidResources)
+		}
+		problems = append(problems, message)
+	}
+	return problems, nil
+}
+
+func firstN(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
@@ -0,0 +1,125 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/operator-framework/operator-controller/internal/permissions"
+)
+
+// PermissionEscalationCheck checks that the service account of a bundle would not escalate its
+// privileges by installing the bundle: it must itself hold each of the permissions the bundle grants
+// to its operator, unless it is allowed to escalate the rules of the roles it creates.
+// It passes when no service account is checked, fails when the service account
+// does not exist, and cannot tell when the objects of the bundle, and so its RBAC, are unknown.
+type PermissionEscalationCheck struct {
+	Client client.Client
+}
+
+func (c *PermissionEscalationCheck) Name() string {
+	return "PermissionEscalation"
+}
+
+func (c *PermissionEscalationCheck) Check(ctx context.Context, bundle *Bundle) error {
+	serviceAccount := bundle.ServiceAccount
+	if serviceAccount == nil {
+		return nil
+	}
+	sa := &metav1.PartialObjectMetadata{}
+	sa.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"})
+	if err := c.Client.Get(ctx, *serviceAccount, sa); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("service account %q not found", serviceAccount.String())
+		}
+		return err
+	}
+	if len(bundle.Objects) == 0 {
+		return &UnknownError{Err: fmt.Errorf("the permissions the bundle grants are unknown, as the catalog does not provide the objects of the bundle")}
+	}
+
+	granted, err := permissions.ForBundle(bundle.Objects, bundle.InstallNamespace, bundle.WatchNamespaces)
+	if err != nil {
+		return fmt.Errorf("failed to read the permissions granted by the bundle: %w", err)
+	}
+	list := granted.List()
+	if len(list) == 0 {
+		return nil
+	}
+
+	// Creating roles with rules the creator does not hold requires the escalate verb on them.
+	canEscalate, err := c.allowed(ctx, *serviceAccount, authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: "escalate", Group: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+	})
+	if err != nil || canEscalate {
+		return err
+	}
+	canEscalateRoles := map[string]bool{}
+
+	var escalations []string
+	for _, permission := range list {
+		if permission.Namespace != "" {
+			allowed, ok := canEscalateRoles[permission.Namespace]
+			if !ok {
+				allowed, err = c.allowed(ctx, *serviceAccount, authorizationv1.SubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{Namespace: permission.Namespace, Verb: "escalate", Group: "rbac.authorization.k8s.io", Resource: "roles"},
+				})
+				if err != nil {
+					return err
+				}
+				canEscalateRoles[permission.Namespace] = allowed
+			}
+			if allowed {
+				continue
+			}
+		}
+
+		allowed, err := c.allowed(ctx, *serviceAccount, permissionReviewSpec(permission))
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			escalations = append(escalations, permission.String())
+		}
+	}
+	if len(escalations) > 0 {
+		return fmt.Errorf("service account %q cannot grant permissions it does not hold: %s", serviceAccount.String(), strings.Join(escalations, ", "))
+	}
+	return nil
+}
+
+// allowed asks the API server whether the service account is allowed to make the given request.
+func (c *PermissionEscalationCheck) allowed(ctx context.Context, serviceAccount types.NamespacedName, spec authorizationv1.SubjectAccessReviewSpec) (bool, error) {
+	spec.User = serviceAccountUser(serviceAccount)
+	spec.Groups = serviceAccountGroups(serviceAccount)
+	review := &authorizationv1.SubjectAccessReview{Spec: spec}
+	if err := c.Client.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("failed to review the permissions of service account %q: %w", serviceAccount.String(), err)
+	}
+	return review.Status.Allowed, nil
+}
+
+// permissionReviewSpec returns the request the given permission allows.
+func permissionReviewSpec(permission permissions.Permission) authorizationv1.SubjectAccessReviewSpec {
+	if permission.NonResourceURL != "" {
+		return authorizationv1.SubjectAccessReviewSpec{
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: permission.NonResourceURL, Verb: permission.Verb},
+		}
+	}
+	return authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: permission.Namespace,
+			Verb:      permission.Verb,
+			Group:     permission.APIGroup,
+			Resource:  permission.Resource,
+			Name:      permission.ResourceName,
+		},
+	}
+}
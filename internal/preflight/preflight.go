@@ -0,0 +1,177 @@
+// Package preflight provides the checks that must pass before the BundleDeployment of an
+// Operator is created or updated.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Bundle is a bundle that is about to be installed, or upgraded to.
+type Bundle struct {
+	// BundleDeployment is the BundleDeployment about to be applied to install the bundle.
+	BundleDeployment *unstructured.Unstructured
+	// CustomResourceDefinitions are the CRDs installed by the bundle. They are only known
+	// when the catalog provides the objects of the bundle.
+	CustomResourceDefinitions []apiextensionsv1.CustomResourceDefinition
+	// Objects are the objects the bundle installs, CRDs included, as rendered and patched for
+	// the Operator. They are only known when the catalog provides all the objects of the bundle,
+	// and are empty otherwise.
+	Objects []unstructured.Unstructured
+	// InstallNamespace is the namespace the namespaced objects of the bundle are installed in.
+	InstallNamespace string
+	// WatchNamespaces are the namespaces the operator of the bundle watches, or empty if it
+	// watches all namespaces.
+	WatchNamespaces []string
+	// ServiceAccount is the service account that must be allowed to manage the objects of the
+	// bundle for it to be installed, or nil if none is checked. The bundle is installed with the
+	// permissions of the provisioner either way.
+	ServiceAccount *types.NamespacedName
+}
+
+// Check is a preflight check of a bundle that is about to be installed.
+type Check interface {
+	// Name identifies the check in the results reported to users.
+	Name() string
+	// Check returns an error describing why the bundle must not be installed,
+	// or nil if the check passed.
+	Check(ctx context.Context, bundle *Bundle) error
+}
+
+// AggregateToPreflightLabel labels the ClusterRoles that grant operator-controller the permissions
+// the preflight checks need beyond their own, e.g. to list the custom resources of the CRDs a bundle
+// upgrades.
+const AggregateToPreflightLabel = "operators.operatorframework.io/aggregate-to-preflight"
+
+// UnknownError reports that a check could not tell whether the bundle passes it, e.g. because
+// the objects of the bundle are unknown. The bundle is not installed until the check can tell.
+type UnknownError struct {
+	Err error
+}
+
+func (e *UnknownError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UnknownError) Unwrap() error {
+	return e.Err
+}
+
+// IsUnknown returns true if the error reports that a check could not tell whether the bundle
+// passes it.
+func IsUnknown(err error) bool {
+	var unknown *UnknownError
+	return errors.As(err, &unknown)
+}
+
+// WarningError reports that a check passed, but could not inspect everything it is meant to, e.g.
+// because operator-controller is not allowed to read part of it. It does not prevent the bundle
+// from being installed.
+type WarningError struct {
+	Err error
+}
+
+func (e *WarningError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *WarningError) Unwrap() error {
+	return e.Err
+}
+
+// IsWarning returns true if the error reports that a check passed with a warning.
+func IsWarning(err error) bool {
+	var warning *WarningError
+	return errors.As(err, &warning)
+}
+
+// Result is the outcome of a preflight check.
+type Result struct {
+	// Name is the name of the check.
+	Name string
+	// Err is the reason the check failed, or nil if the check passed. It is an *UnknownError if
+	// the check could not tell whether the bundle passes it, and a *WarningError if the check
+	// passed with a warning.
+	Err error
+}
+
+// Run runs each of the checks against the bundle, and returns their results.
+// It returns true if all checks passed, possibly with warnings.
+func Run(ctx context.Context, checks []Check, bundle *Bundle) ([]Result, bool) {
+	results := make([]Result, 0, len(checks))
+	passed := true
+	for _, check := range checks {
+		err := check.Check(ctx, bundle)
+		if err != nil && !IsWarning(err) {
+			passed = false
+		}
+		results = append(results, Result{Name: check.Name(), Err: err})
+	}
+	return results, passed
+}
+
+// DefaultChecks returns the built-in preflight checks: the BundleDeployment must not be controlled
+// by another object, the CRDs of the bundle must not break the existing custom resources, the service
+// account the bundle is installed with must be allowed to manage its objects without escalating its
+// privileges through the permissions the bundle grants, and the BundleDeployment must be accepted by
+// a server-side dry-run apply as the given field owner.
+func DefaultChecks(c client.Client, fieldOwner string) []Check {
+	return []Check{
+		&OwnershipCheck{Client: c},
+		&CRDUpgradeSafetyCheck{Client: c},
+		&ServiceAccountPermissionsCheck{Client: c},
+		&PermissionEscalationCheck{Client: c},
+		&DryRunApplyCheck{Client: c, FieldOwner: fieldOwner},
+	}
+}
+
+// OwnershipCheck checks that an existing BundleDeployment is not controlled by an object other
+// than the controller of the BundleDeployment about to be applied, so that it is not taken over.
+type OwnershipCheck struct {
+	Client client.Reader
+}
+
+func (c *OwnershipCheck) Name() string {
+	return "Ownership"
+}
+
+func (c *OwnershipCheck) Check(ctx context.Context, bundle *Bundle) error {
+	bundleDeployment := bundle.BundleDeployment
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(bundleDeployment.GroupVersionKind())
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: bundleDeployment.GetNamespace(), Name: bundleDeployment.GetName()}, existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	existingController := metav1.GetControllerOfNoCopy(existing)
+	if existingController == nil {
+		return nil
+	}
+	if controller := metav1.GetControllerOfNoCopy(bundleDeployment); controller != nil && controller.UID == existingController.UID {
+		return nil
+	}
+	return fmt.Errorf("%s %q is already controlled by %s %q", existing.GetKind(), existing.GetName(), existingController.Kind, existingController.Name)
+}
+
+// DryRunApplyCheck checks that the API server accepts the BundleDeployment, by applying it
+// server-side in dry-run mode. This surfaces the errors of validation and admission control
+// before anything is changed.
+type DryRunApplyCheck struct {
+	Client     client.Client
+	FieldOwner string
+}
+
+func (c *DryRunApplyCheck) Name() string {
+	return "DryRunApply"
+}
+
+func (c *DryRunApplyCheck) Check(ctx context.Context, bundle *Bundle) error {
+	// the dry-run response is written into the object, which must be left untouched
+	return c.Client.Patch(ctx, bundle.BundleDeployment.DeepCopy(), client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner(c.FieldOwner))
+}
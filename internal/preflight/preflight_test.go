@@ -0,0 +1,475 @@
+package preflight_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/operator-framework/operator-controller/internal/preflight"
+)
+
+var _ = Describe("Preflight", func() {
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+	)
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(rukpakv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	Describe("Run", func() {
+		It("returns the result of each check", func() {
+			results, passed := preflight.Run(ctx, []preflight.Check{
+				checkFunc{name: "Passing"},
+				checkFunc{name: "Failing", err: errors.New("failed")},
+			}, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})
+			Expect(passed).To(BeFalse())
+			Expect(results).To(Equal([]preflight.Result{
+				{Name: "Passing"},
+				{Name: "Failing", Err: errors.New("failed")},
+			}))
+		})
+		It("passes when all checks pass", func() {
+			_, passed := preflight.Run(ctx, []preflight.Check{checkFunc{name: "Passing"}}, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})
+			Expect(passed).To(BeTrue())
+		})
+		It("passes when checks pass with warnings", func() {
+			_, passed := preflight.Run(ctx, []preflight.Check{
+				checkFunc{name: "Passing"},
+				checkFunc{name: "Warning", err: &preflight.WarningError{Err: errors.New("not everything was checked")}},
+			}, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})
+			Expect(passed).To(BeTrue())
+		})
+	})
+
+	Describe("OwnershipCheck", func() {
+		It("passes when the bundleDeployment does not exist", func() {
+			check := &preflight.OwnershipCheck{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			Expect(check.Check(ctx, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})).To(Succeed())
+		})
+		It("passes when the bundleDeployment has the same controller", func() {
+			check := &preflight.OwnershipCheck{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(bundleDeployment("test", "operator-uid")).Build()}
+			Expect(check.Check(ctx, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})).To(Succeed())
+		})
+		It("passes when the bundleDeployment has no controller", func() {
+			check := &preflight.OwnershipCheck{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(bundleDeployment("test", "")).Build()}
+			Expect(check.Check(ctx, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})).To(Succeed())
+		})
+		It("fails when the bundleDeployment has another controller", func() {
+			check := &preflight.OwnershipCheck{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(bundleDeployment("test", "other-uid")).Build()}
+			err := check.Check(ctx, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})
+			Expect(err).To(MatchError(`BundleDeployment "test" is already controlled by Operator "test"`))
+		})
+	})
+
+	Describe("DryRunApplyCheck", func() {
+		It("applies the bundleDeployment in dry-run mode", func() {
+			recorder := &patchRecorder{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			check := &preflight.DryRunApplyCheck{Client: recorder, FieldOwner: "operator-controller"}
+			bd := bundleDeployment("test", "operator-uid")
+			Expect(check.Check(ctx, &preflight.Bundle{BundleDeployment: bd})).To(Succeed())
+
+			Expect(recorder.patchType).To(Equal(client.Apply.Type()))
+			Expect(recorder.options.DryRun).To(Equal([]string{metav1.DryRunAll}))
+			Expect(recorder.options.FieldManager).To(Equal("operator-controller"))
+			Expect(recorder.options.Force).To(Equal(pointer.Bool(true)))
+			Expect(recorder.obj).NotTo(BeIdenticalTo(bd))
+			Expect(recorder.obj).To(Equal(bd))
+		})
+		It("fails when the dry-run apply is rejected", func() {
+			recorder := &patchRecorder{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), err: errors.New("denied by admission webhook")}
+			check := &preflight.DryRunApplyCheck{Client: recorder, FieldOwner: "operator-controller"}
+			Expect(check.Check(ctx, &preflight.Bundle{BundleDeployment: bundleDeployment("test", "operator-uid")})).To(MatchError("denied by admission webhook"))
+		})
+	})
+
+	Describe("CRDUpgradeSafetyCheck", func() {
+		var existingCRD *apiextensionsv1.CustomResourceDefinition
+		BeforeEach(func() {
+			Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+			scheme.AddKnownTypeWithName(widgetGVK, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(widgetGVK.GroupVersion().WithKind("WidgetList"), &unstructured.UnstructuredList{})
+			existingCRD = widgetCRD(&apiextensionsv1.JSONSchemaProps{Type: "object"})
+			existingCRD.Status.StoredVersions = []string{"v1"}
+		})
+
+		It("passes when the CRD is not installed", func() {
+			check := &preflight.CRDUpgradeSafetyCheck{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			bundle := &preflight.Bundle{CustomResourceDefinitions: []apiextensionsv1.CustomResourceDefinition{*widgetCRD(requiredColorSchema())}}
+			Expect(check.Check(ctx, bundle)).To(Succeed())
+		})
+		It("passes when the existing custom resources are valid", func() {
+			check := &preflight.CRDUpgradeSafetyCheck{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(existingCRD, widget("blue", "blue")).Build()}
+			bundle := &preflight.Bundle{CustomResourceDefinitions: []apiextensionsv1.CustomResourceDefinition{*widgetCRD(requiredColorSchema())}}
+			Expect(check.Check(ctx, bundle)).To(Succeed())
+		})
+		It("fails when a stored version is removed", func() {
+			check := &preflight.CRDUpgradeSafetyCheck{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(existingCRD).Build()}
+			newCRD := widgetCRD(&apiextensionsv1.JSONSchemaProps{Type: "object"})
+			newCRD.Spec.Versions[0].Name = "v2"
+			err := check.Check(ctx, &preflight.Bundle{CustomResourceDefinitions: []apiextensionsv1.CustomResourceDefinition{*newCRD}})
+			Expect(err).To(MatchError("CustomResourceDefinition widgets.example.com removes version v1, which is still listed in status.storedVersions"))
+		})
+		It("fails when existing custom resources are invalid under the new schema", func() {
+			check := &preflight.CRDUpgradeSafetyCheck{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(existingCRD, widget("blue", "blue"), widget("plain", "")).Build()}
+			bundle := &preflight.Bundle{CustomResourceDefinitions: []apiextensionsv1.CustomResourceDefinition{*widgetCRD(requiredColorSchema())}}
+			err := check.Check(ctx, bundle)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("version v1 of CustomResourceDefinition widgets.example.com does not accept 1 existing widgets: default/plain: "))
+			Expect(err.Error()).To(ContainSubstring("spec.color: Required value"))
+		})
+		It("warns when it is not allowed to list the existing custom resources", func() {
+			check := &preflight.CRDUpgradeSafetyCheck{Client: &forbiddenLister{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(existingCRD, widget("plain", "")).Build()}}
+			bundle := &preflight.Bundle{CustomResourceDefinitions: []apiextensionsv1.CustomResourceDefinition{*widgetCRD(requiredColorSchema())}}
+			err := check.Check(ctx, bundle)
+			Expect(preflight.IsWarning(err)).To(BeTrue())
+			Expect(err).To(MatchError("the existing widgets were not checked, as operator-controller is not allowed to list them: grant it the permission in a ClusterRole labeled operators.operatorframework.io/aggregate-to-preflight=true"))
+		})
+		It("still fails when a stored version is removed but the existing custom resources cannot be listed", func() {
+			existingCRD.Status.StoredVersions = []string{"v1alpha1", "v1"}
+			check := &preflight.CRDUpgradeSafetyCheck{Client: &forbiddenLister{Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(existingCRD).Build()}}
+			bundle := &preflight.Bundle{CustomResourceDefinitions: []apiextensionsv1.CustomResourceDefinition{*widgetCRD(requiredColorSchema())}}
+			err := check.Check(ctx, bundle)
+			Expect(preflight.IsWarning(err)).To(BeFalse())
+			Expect(err).To(MatchError("CustomResourceDefinition widgets.example.com removes version v1alpha1, which is still listed in status.storedVersions"))
+		})
+	})
+
+	Describe("ServiceAccountPermissionsCheck", func() {
+		var (
+			serviceAccount *corev1.ServiceAccount
+			bundle         *preflight.Bundle
+		)
+		BeforeEach(func() {
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(authorizationv1.AddToScheme(scheme)).To(Succeed())
+			serviceAccount = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "operators", Name: "installer"}}
+			deployment := unstructured.Unstructured{}
+			deployment.SetAPIVersion("apps/v1")
+			deployment.SetKind("Deployment")
+			clusterRole := unstructured.Unstructured{}
+			clusterRole.SetAPIVersion("rbac.authorization.k8s.io/v1")
+			clusterRole.SetKind("ClusterRole")
+			service := unstructured.Unstructured{}
+			service.SetAPIVersion("v1")
+			service.SetKind("Service")
+			bundle = &preflight.Bundle{
+				Objects:          []unstructured.Unstructured{deployment, clusterRole, service},
+				InstallNamespace: "operators",
+				ServiceAccount:   &types.NamespacedName{Namespace: "operators", Name: "installer"},
+			}
+		})
+		It("passes when no service account is checked", func() {
+			check := &preflight.ServiceAccountPermissionsCheck{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			Expect(check.Check(ctx, &preflight.Bundle{})).To(Succeed())
+		})
+		It("fails when the service account does not exist", func() {
+			check := &preflight.ServiceAccountPermissionsCheck{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			Expect(check.Check(ctx, bundle)).To(MatchError(`service account "operators/installer" not found`))
+		})
+		It("passes when the service account is allowed to manage the objects of the bundle", func() {
+			reviewer := &accessReviewer{Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(restMapper()).WithObjects(serviceAccount).Build()}
+			check := &preflight.ServiceAccountPermissionsCheck{Client: reviewer}
+			Expect(check.Check(ctx, bundle)).To(Succeed())
+			Expect(reviewer.reviewed).To(ContainElements(
+				"create services in namespace operators",
+				"get services in namespace operators",
+				"patch deployments.apps in namespace operators",
+				"list clusterroles.rbac.authorization.k8s.io",
+				"delete clusterroles.rbac.authorization.k8s.io",
+			))
+			Expect(reviewer.reviewed).To(HaveLen(21))
+			Expect(reviewer.user).To(Equal("system:serviceaccount:operators:installer"))
+		})
+		It("lists what the service account is not allowed to do", func() {
+			reviewer := &accessReviewer{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(restMapper()).WithObjects(serviceAccount).Build(),
+				denied: sets.NewString("create clusterroles.rbac.authorization.k8s.io", "delete services in namespace operators"),
+			}
+			check := &preflight.ServiceAccountPermissionsCheck{Client: reviewer}
+			Expect(check.Check(ctx, bundle)).To(MatchError(`service account "operators/installer" is not allowed to create clusterroles.rbac.authorization.k8s.io, delete services in namespace operators`))
+		})
+		It("cannot tell when the objects of the bundle are unknown", func() {
+			check := &preflight.ServiceAccountPermissionsCheck{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(serviceAccount).Build()}
+			bundle.Objects = nil
+			err := check.Check(ctx, bundle)
+			Expect(preflight.IsUnknown(err)).To(BeTrue())
+			Expect(err).To(MatchError(`the permissions service account "operators/installer" needs are unknown, as the catalog does not provide the objects of the bundle`))
+		})
+	})
+
+	Describe("PermissionEscalationCheck", func() {
+		var (
+			serviceAccount *corev1.ServiceAccount
+			bundle         *preflight.Bundle
+		)
+		BeforeEach(func() {
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(authorizationv1.AddToScheme(scheme)).To(Succeed())
+			serviceAccount = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "operators", Name: "installer"}}
+			csv := unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "operators.coreos.com/v1alpha1",
+				"kind":       "ClusterServiceVersion",
+				"spec": map[string]interface{}{"install": map[string]interface{}{"spec": map[string]interface{}{
+					"permissions": []interface{}{map[string]interface{}{
+						"rules": []interface{}{map[string]interface{}{
+							"apiGroups": []interface{}{""},
+							"resources": []interface{}{"secrets"},
+							"verbs":     []interface{}{"get"},
+						}},
+					}},
+					"clusterPermissions": []interface{}{map[string]interface{}{
+						"rules": []interface{}{
+							map[string]interface{}{
+								"apiGroups": []interface{}{""},
+								"resources": []interface{}{"namespaces"},
+								"verbs":     []interface{}{"list"},
+							},
+							map[string]interface{}{
+								"nonResourceURLs": []interface{}{"/metrics"},
+								"verbs":           []interface{}{"get"},
+							},
+						},
+					}},
+				}}},
+			}}
+			bundle = &preflight.Bundle{
+				Objects:          []unstructured.Unstructured{csv},
+				InstallNamespace: "operators",
+				WatchNamespaces:  []string{"operators"},
+				ServiceAccount:   &types.NamespacedName{Namespace: "operators", Name: "installer"},
+			}
+		})
+		It("passes when no service account is checked", func() {
+			check := &preflight.PermissionEscalationCheck{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			Expect(check.Check(ctx, &preflight.Bundle{})).To(Succeed())
+		})
+		It("fails when the service account does not exist", func() {
+			check := &preflight.PermissionEscalationCheck{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			Expect(check.Check(ctx, bundle)).To(MatchError(`service account "operators/installer" not found`))
+		})
+		It("cannot tell when the objects of the bundle are unknown", func() {
+			check := &preflight.PermissionEscalationCheck{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(serviceAccount).Build()}
+			bundle.Objects = nil
+			err := check.Check(ctx, bundle)
+			Expect(preflight.IsUnknown(err)).To(BeTrue())
+			Expect(err).To(MatchError("the permissions the bundle grants are unknown, as the catalog does not provide the objects of the bundle"))
+		})
+		It("passes when the service account is allowed to escalate the rules of cluster roles", func() {
+			reviewer := &accessReviewer{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(serviceAccount).Build()}
+			check := &preflight.PermissionEscalationCheck{Client: reviewer}
+			Expect(check.Check(ctx, bundle)).To(Succeed())
+			Expect(reviewer.reviewed).To(Equal([]string{"escalate clusterroles.rbac.authorization.k8s.io"}))
+		})
+		It("passes when the service account holds the permissions the bundle grants", func() {
+			reviewer := &accessReviewer{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(serviceAccount).Build(),
+				denied: sets.NewString("escalate clusterroles.rbac.authorization.k8s.io", "escalate roles.rbac.authorization.k8s.io in namespace operators"),
+			}
+			check := &preflight.PermissionEscalationCheck{Client: reviewer}
+			Expect(check.Check(ctx, bundle)).To(Succeed())
+			Expect(reviewer.reviewed).To(ContainElements("get /metrics", "list namespaces", "get secrets in namespace operators"))
+			Expect(reviewer.user).To(Equal("system:serviceaccount:operators:installer"))
+		})
+		It("lists each permission the service account would escalate to", func() {
+			reviewer := &accessReviewer{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(serviceAccount).Build(),
+				denied: sets.NewString(
+					"escalate clusterroles.rbac.authorization.k8s.io",
+					"escalate roles.rbac.authorization.k8s.io in namespace operators",
+					"get /metrics",
+					"get secrets in namespace operators",
+				),
+			}
+			check := &preflight.PermissionEscalationCheck{Client: reviewer}
+			Expect(check.Check(ctx, bundle)).To(MatchError(`service account "operators/installer" cannot grant permissions it does not hold: get /metrics, get secrets in namespace operators`))
+		})
+	})
+})
+
+func bundleDeployment(name, controllerUID string) *unstructured.Unstructured {
+	bd := &unstructured.Unstructured{}
+	bd.SetGroupVersionKind(rukpakv1alpha1.GroupVersion.WithKind(rukpakv1alpha1.BundleDeploymentKind))
+	bd.SetName(name)
+	if controllerUID != "" {
+		bd.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: "operators.operatorframework.io/v1alpha1",
+			Kind:       "Operator",
+			Name:       name,
+			UID:        types.UID(controllerUID),
+			Controller: pointer.Bool(true),
+		}})
+	}
+	return bd
+}
+
+type checkFunc struct {
+	name string
+	err  error
+}
+
+func (c checkFunc) Name() string {
+	return c.name
+}
+
+func (c checkFunc) Check(context.Context, *preflight.Bundle) error {
+	return c.err
+}
+
+// forbiddenLister is a client that is not allowed to list unstructured objects.
+type forbiddenLister struct {
+	client.Client
+}
+
+func (c *forbiddenLister) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*unstructured.UnstructuredList); ok {
+		return apierrors.NewForbidden(schema.GroupResource{Group: "example.com", Resource: "widgets"}, "", errors.New("not today"))
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// patchRecorder records the patch made through it instead of sending it.
+type patchRecorder struct {
+	client.Client
+	obj       client.Object
+	patchType types.PatchType
+	options   client.PatchOptions
+	err       error
+}
+
+func (p *patchRecorder) Patch(_ context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	p.obj = obj
+	p.patchType = patch.Type()
+	p.options.ApplyOptions(opts)
+	return p.err
+}
+
+// restMapper maps the kinds of the objects installed by a bundle to their resources.
+func restMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ServiceAccount"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("Role"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"), meta.RESTScopeRoot)
+	return mapper
+}
+
+// accessReviewer answers SubjectAccessReviews, allowing every request except the denied ones.
+type accessReviewer struct {
+	client.Client
+	denied   sets.String
+	reviewed []string
+	user     string
+}
+
+func (r *accessReviewer) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	review, ok := obj.(*authorizationv1.SubjectAccessReview)
+	if !ok {
+		return r.Client.Create(ctx, obj, opts...)
+	}
+	var request string
+	if attributes := review.Spec.NonResourceAttributes; attributes != nil {
+		request = fmt.Sprintf("%s %s", attributes.Verb, attributes.Path)
+	} else {
+		attributes := review.Spec.ResourceAttributes
+		resource := attributes.Resource
+		if attributes.Group != "" {
+			resource += "." + attributes.Group
+		}
+		if attributes.Name != "" {
+			resource += " named " + attributes.Name
+		}
+		request = fmt.Sprintf("%s %s", attributes.Verb, resource)
+		if attributes.Namespace != "" {
+			request += " in namespace " + attributes.Namespace
+		}
+	}
+	r.reviewed = append(r.reviewed, request)
+	r.user = review.Spec.User
+	review.Status.Allowed = !r.denied.Has(request)
+	return nil
+}
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+func widgetCRD(openAPIV3Schema *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: widgetGVK.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "widgets",
+				Singular: "widget",
+				Kind:     widgetGVK.Kind,
+				ListKind: "WidgetList",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    widgetGVK.Version,
+				Served:  true,
+				Storage: true,
+				Schema:  &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: openAPIV3Schema},
+			}},
+		},
+	}
+}
+
+// requiredColorSchema is a schema requiring spec.color to be set.
+func requiredColorSchema() *apiextensionsv1.JSONSchemaProps {
+	return &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {
+				Type:     "object",
+				Required: []string{"color"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"color": {Type: "string"},
+				},
+			},
+		},
+	}
+}
+
+func widget(name, color string) *unstructured.Unstructured {
+	w := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+	w.SetGroupVersionKind(widgetGVK)
+	w.SetNamespace("default")
+	w.SetName(name)
+	if color != "" {
+		Expect(unstructured.SetNestedField(w.Object, color, "spec", "color")).To(Succeed())
+	}
+	return w
+}
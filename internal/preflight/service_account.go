@@ -0,0 +1,148 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// serviceAccountVerbs are the verbs a service account needs on the objects of a bundle to
+// install and manage them.
+var serviceAccountVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// ServiceAccountPermissionsCheck checks that the service account of a bundle exists, and is allowed
+// to manage the objects of the bundle, by asking the API server with SubjectAccessReviews. It passes
+// when no service account is checked, and cannot tell when the objects of the bundle are unknown.
+type ServiceAccountPermissionsCheck struct {
+	Client client.Client
+}
+
+func (c *ServiceAccountPermissionsCheck) Name() string {
+	return "ServiceAccountPermissions"
+}
+
+func (c *ServiceAccountPermissionsCheck) Check(ctx context.Context, bundle *Bundle) error {
+	serviceAccount := bundle.ServiceAccount
+	if serviceAccount == nil {
+		return nil
+	}
+	sa := &metav1.PartialObjectMetadata{}
+	sa.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"})
+	if err := c.Client.Get(ctx, *serviceAccount, sa); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("service account %q not found", serviceAccount.String())
+		}
+		return err
+	}
+	if len(bundle.Objects) == 0 {
+		return &UnknownError{Err: fmt.Errorf("the permissions service account %q needs are unknown, as the catalog does not provide the objects of the bundle", serviceAccount.String())}
+	}
+
+	denied := sets.NewString()
+	for _, attributes := range c.resourceAttributes(bundle) {
+		attributes := attributes
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:               serviceAccountUser(*serviceAccount),
+				Groups:             serviceAccountGroups(*serviceAccount),
+				ResourceAttributes: &attributes,
+			},
+		}
+		if err := c.Client.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to review the permissions of service account %q: %w", serviceAccount.String(), err)
+		}
+		if !review.Status.Allowed {
+			denied.Insert(describeResourceAttributes(attributes))
+		}
+	}
+	if denied.Len() > 0 {
+		return fmt.Errorf("service account %q is not allowed to %s", serviceAccount.String(), strings.Join(denied.List(), ", "))
+	}
+	return nil
+}
+
+// resourceAttributes returns the attributes of the requests the service account must be allowed
+// to make to manage the objects of the bundle.
+func (c *ServiceAccountPermissionsCheck) resourceAttributes(bundle *Bundle) []authorizationv1.ResourceAttributes {
+	type target struct {
+		gvk       schema.GroupVersionKind
+		namespace string
+	}
+	targets := make([]target, 0, len(bundle.Objects))
+	for i := range bundle.Objects {
+		obj := &bundle.Objects[i]
+		targets = append(targets, target{gvk: obj.GroupVersionKind(), namespace: obj.GetNamespace()})
+	}
+
+	seen := map[authorizationv1.ResourceAttributes]struct{}{}
+	var attributes []authorizationv1.ResourceAttributes
+	for _, t := range targets {
+		resource, namespaced := c.resourceFor(t.gvk)
+		namespace := ""
+		if namespaced {
+			namespace = t.namespace
+			if namespace == "" {
+				namespace = bundle.InstallNamespace
+			}
+		}
+		for _, verb := range serviceAccountVerbs {
+			a := authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     resource.Group,
+				Version:   resource.Version,
+				Resource:  resource.Resource,
+			}
+			if _, ok := seen[a]; ok {
+				continue
+			}
+			seen[a] = struct{}{}
+			attributes = append(attributes, a)
+		}
+	}
+	sort.SliceStable(attributes, func(i, j int) bool {
+		return describeResourceAttributes(attributes[i]) < describeResourceAttributes(attributes[j])
+	})
+	return attributes
+}
+
+// resourceFor returns the resource of the given kind, and whether it is namespaced. Kinds unknown to
+// the API server, e.g. those defined by the CRDs of the bundle, are guessed to be namespaced.
+func (c *ServiceAccountPermissionsCheck) resourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, bool) {
+	mapping, err := c.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		resource, _ := meta.UnsafeGuessKindToResource(gvk)
+		return resource, true
+	}
+	return mapping.Resource, mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
+
+func serviceAccountUser(serviceAccount types.NamespacedName) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", serviceAccount.Namespace, serviceAccount.Name)
+}
+
+func serviceAccountGroups(serviceAccount types.NamespacedName) []string {
+	return []string{"system:serviceaccounts", fmt.Sprintf("system:serviceaccounts:%s", serviceAccount.Namespace), "system:authenticated"}
+}
+
+// describeResourceAttributes describes a request, e.g. "create deployments.apps in namespace foo".
+func describeResourceAttributes(attributes authorizationv1.ResourceAttributes) string {
+	resource := attributes.Resource
+	if attributes.Group != "" {
+		resource = fmt.Sprintf("%s.%s", attributes.Resource, attributes.Group)
+	}
+	if attributes.Namespace == "" {
+		return fmt.Sprintf("%s %s", attributes.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", attributes.Verb, resource, attributes.Namespace)
+}
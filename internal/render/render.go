@@ -0,0 +1,280 @@
+// Package render renders the objects of a registry+v1 bundle into the plain objects installed for an
+// Operator, in the namespace and for the namespaces the Operator asks for.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/davecgh/go-spew/spew"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// TargetNamespacesAnnotation tells the operator the namespaces it watches, all of them if empty.
+	TargetNamespacesAnnotation = "olm.targetNamespaces"
+
+	maxNameLength = 63
+)
+
+// csvPermission grants rules to a service account of the operator.
+type csvPermission struct {
+	ServiceAccountName string              `json:"serviceAccountName"`
+	Rules              []rbacv1.PolicyRule `json:"rules"`
+
+	// name is the name of the roles and bindings granting the permission.
+	name string
+}
+
+// csvDeployment is a Deployment defined by the install strategy of a ClusterServiceVersion.
+type csvDeployment struct {
+	Name  string                 `json:"name"`
+	Label map[string]string      `json:"label,omitempty"`
+	Spec  map[string]interface{} `json:"spec"`
+}
+
+// csvInstallStrategy is the install strategy of a ClusterServiceVersion.
+type csvInstallStrategy struct {
+	Deployments        []csvDeployment `json:"deployments"`
+	Permissions        []csvPermission `json:"permissions"`
+	ClusterPermissions []csvPermission `json:"clusterPermissions"`
+}
+
+// RegistryV1 returns the objects installed from the given objects of a registry+v1 bundle, the way the
+// registry provisioner of rukpak converts a bundle: the ClusterServiceVersion is replaced by the
+// namespace, service accounts, RBAC and Deployments it defines, and the other objects are kept as is.
+// The objects are named the way the provisioner names them, so that switching a BundleDeployment
+// between the bundle image and the rendered objects upgrades the same objects. Like the provisioner,
+// it does not support the webhooks and API services a ClusterServiceVersion may define.
+// The Deployments are installed in the install namespace and watch the given namespaces, or all of
+// them if none are given. The namespaced permissions of the operator are granted in the install
+// namespace and in each of the watched namespaces, or cluster-wide if it watches all namespaces.
+// Whether the bundle supports watching the given namespaces is not checked.
+func RegistryV1(objects []unstructured.Unstructured, installNamespace string, watchNamespaces []string) ([]unstructured.Unstructured, error) {
+	var csv *unstructured.Unstructured
+	var others []unstructured.Unstructured
+	for i := range objects {
+		if objects[i].GetKind() != "ClusterServiceVersion" {
+			others = append(others, *objects[i].DeepCopy())
+			continue
+		}
+		if csv != nil {
+			return nil, fmt.Errorf("the bundle has more than one ClusterServiceVersion: %q and %q", csv.GetName(), objects[i].GetName())
+		}
+		csv = &objects[i]
+	}
+	if csv == nil {
+		return nil, fmt.Errorf("the bundle has no ClusterServiceVersion")
+	}
+	if owned, _, _ := unstructured.NestedSlice(csv.Object, "spec", "apiservicedefinitions", "owned"); len(owned) > 0 {
+		return nil, fmt.Errorf("ClusterServiceVersion %q: apiServiceDefinitions are not supported by rukpak", csv.GetName())
+	}
+	if webhooks, _, _ := unstructured.NestedSlice(csv.Object, "spec", "webhookdefinitions"); len(webhooks) > 0 {
+		return nil, fmt.Errorf("ClusterServiceVersion %q: webhookDefinitions are not supported by rukpak", csv.GetName())
+	}
+	strategy, err := installStrategy(csv)
+	if err != nil {
+		return nil, err
+	}
+
+	targetNamespaces := strings.Join(watchNamespaces, ",")
+	serviceAccounts := sets.NewString()
+	var deployments []unstructured.Unstructured
+	for _, d := range strategy.Deployments {
+		deployment, err := newDeployment(csv.GetAnnotations(), d, installNamespace, targetNamespaces)
+		if err != nil {
+			return nil, fmt.Errorf("ClusterServiceVersion %q: deployment %q: %w", csv.GetName(), d.Name, err)
+		}
+		deployments = append(deployments, *deployment)
+		serviceAccounts.Insert(serviceAccountNameOrDefault(deployment.Object))
+	}
+	for _, permission := range append(strategy.Permissions, strategy.ClusterPermissions...) {
+		serviceAccounts.Insert(serviceAccountOrDefault(permission.ServiceAccountName))
+	}
+
+	permissions, clusterPermissions := strategy.Permissions, strategy.ClusterPermissions
+	for _, p := range [][]csvPermission{permissions, clusterPermissions} {
+		for i := range p {
+			p[i].name = rbacName(csv.GetName(), p[i])
+		}
+	}
+	operatorNamespaces := sets.NewString(watchNamespaces...)
+	if operatorNamespaces.Len() == 0 {
+		// an operator watching all namespaces is granted its namespaced permissions cluster-wide
+		for _, permission := range permissions {
+			rules := make([]rbacv1.PolicyRule, 0, len(permission.Rules)+1)
+			permission.Rules = append(append(rules, permission.Rules...), rbacv1.PolicyRule{
+				Verbs:     []string{"get", "list", "watch"},
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"namespaces"},
+			})
+			clusterPermissions = append(clusterPermissions, permission)
+		}
+		permissions = nil
+	} else {
+		operatorNamespaces.Insert(installNamespace)
+	}
+
+	rendered := []runtime.Object{&corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: installNamespace},
+	}}
+	for _, name := range serviceAccounts.List() {
+		if name == "default" {
+			continue
+		}
+		rendered = append(rendered, &corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: installNamespace},
+		})
+	}
+	var roleBindings []runtime.Object
+	for _, permission := range permissions {
+		saName := serviceAccountOrDefault(permission.ServiceAccountName)
+		name := permission.name
+		for _, namespace := range operatorNamespaces.List() {
+			rendered = append(rendered, &rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Rules:      permission.Rules,
+			})
+			roleBindings = append(roleBindings, &rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: saName, Namespace: installNamespace}},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+			})
+		}
+	}
+	rendered = append(rendered, roleBindings...)
+	var clusterRoleBindings []runtime.Object
+	for _, permission := range clusterPermissions {
+		saName := serviceAccountOrDefault(permission.ServiceAccountName)
+		name := permission.name
+		rendered = append(rendered, &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules:      permission.Rules,
+		})
+		clusterRoleBindings = append(clusterRoleBindings, &rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: saName, Namespace: installNamespace}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: name},
+		})
+	}
+	rendered = append(rendered, clusterRoleBindings...)
+
+	result := make([]unstructured.Unstructured, 0, len(rendered)+len(others)+len(deployments))
+	for _, obj := range rendered {
+		fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, unstructured.Unstructured{Object: fields})
+	}
+	result = append(result, others...)
+	return append(result, deployments...), nil
+}
+
+// installStrategy reads the install strategy of the ClusterServiceVersion.
+func installStrategy(csv *unstructured.Unstructured) (*csvInstallStrategy, error) {
+	fields, _, err := unstructured.NestedMap(csv.Object, "spec", "install", "spec")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the install strategy of ClusterServiceVersion %q: %w", csv.GetName(), err)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	strategy := &csvInstallStrategy{}
+	if err := json.Unmarshal(data, strategy); err != nil {
+		return nil, fmt.Errorf("failed to read the install strategy of ClusterServiceVersion %q: %w", csv.GetName(), err)
+	}
+	return strategy, nil
+}
+
+// newDeployment returns the Deployment defined by the install strategy, in the install namespace and
+// annotated with the namespaces it watches, on the Deployment and, unless it watches all namespaces,
+// on its pods.
+func newDeployment(csvAnnotations map[string]string, d csvDeployment, installNamespace, targetNamespaces string) (*unstructured.Unstructured, error) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	deployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	deployment.SetName(d.Name)
+	deployment.SetNamespace(installNamespace)
+	deployment.SetLabels(d.Label)
+	spec := runtime.DeepCopyJSON(d.Spec)
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	deployment.Object["spec"] = spec
+
+	podAnnotations, _, err := unstructured.NestedStringMap(spec, "template", "metadata", "annotations")
+	if err != nil {
+		return nil, err
+	}
+	annotations := make(map[string]string, len(csvAnnotations)+len(podAnnotations)+1)
+	for k, v := range csvAnnotations {
+		annotations[k] = v
+	}
+	for k, v := range podAnnotations {
+		annotations[k] = v
+	}
+	annotations[TargetNamespacesAnnotation] = targetNamespaces
+	deployment.SetAnnotations(annotations)
+
+	// the pods of an operator watching all namespaces are left as the registry provisioner of rukpak
+	// installs them, so that they are not restarted when operator-controller renders the bundle instead
+	if targetNamespaces == "" {
+		return deployment, nil
+	}
+	if podAnnotations == nil {
+		podAnnotations = map[string]string{}
+	}
+	podAnnotations[TargetNamespacesAnnotation] = targetNamespaces
+	if err := unstructured.SetNestedStringMap(spec, podAnnotations, "template", "metadata", "annotations"); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// serviceAccountNameOrDefault returns the service account the pods of the Deployment run as.
+func serviceAccountNameOrDefault(deployment map[string]interface{}) string {
+	name, _, _ := unstructured.NestedString(deployment, "spec", "template", "spec", "serviceAccountName")
+	return serviceAccountOrDefault(name)
+}
+
+func serviceAccountOrDefault(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+// rbacName returns the name of the roles and bindings granting the permission of the
+// ClusterServiceVersion: the name the registry provisioner of rukpak gives them, so that the objects it
+// installed are upgraded in place when operator-controller renders the bundle instead. The provisioner
+// suffixes the name of the ClusterServiceVersion and of the service account with a hash of the spew
+// representation of the ClusterServiceVersion name and of the typed permission of
+// github.com/operator-framework/api, which is reproduced here.
+func rbacName(csvName string, permission csvPermission) string {
+	printer := spew.ConfigState{Indent: " ", SortKeys: true, DisableMethods: true, SpewKeys: true}
+	hasher := fnv.New32a()
+	_, _ = fmt.Fprintf(hasher, "([]interface {})[(string)%s (v1alpha1.StrategyDeploymentPermissions){ServiceAccountName:(string)%s Rules:%s}]",
+		csvName, permission.ServiceAccountName, printer.Sprintf("%#v", permission.Rules))
+	hash := rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+	base := fmt.Sprintf("%s-%s", csvName, serviceAccountOrDefault(permission.ServiceAccountName))
+	if len(base)+len(hash) > maxNameLength {
+		base = base[:maxNameLength-len(hash)-1]
+	}
+	return fmt.Sprintf("%s-%s", base, hash)
+}
@@ -0,0 +1,132 @@
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/operator-framework/operator-controller/internal/render"
+)
+
+var _ = Describe("RegistryV1", func() {
+	var objects []unstructured.Unstructured
+	BeforeEach(func() {
+		objects = []unstructured.Unstructured{
+			{Object: map[string]interface{}{
+				"apiVersion": "operators.coreos.com/v1alpha1",
+				"kind":       "ClusterServiceVersion",
+				"metadata": map[string]interface{}{
+					"name":        "foo.v1.0.0",
+					"annotations": map[string]interface{}{"operatorframework.io/suggested-namespace": "foo-system"},
+				},
+				"spec": map[string]interface{}{"install": map[string]interface{}{"strategy": "deployment", "spec": map[string]interface{}{
+					"permissions": []interface{}{map[string]interface{}{
+						"serviceAccountName": "foo-operator",
+						"rules":              []interface{}{map[string]interface{}{"apiGroups": []interface{}{""}, "resources": []interface{}{"configmaps"}, "verbs": []interface{}{"get"}}},
+					}},
+					"clusterPermissions": []interface{}{map[string]interface{}{
+						"serviceAccountName": "foo-operator",
+						"rules":              []interface{}{map[string]interface{}{"apiGroups": []interface{}{"foo.io"}, "resources": []interface{}{"foos"}, "verbs": []interface{}{"list"}}},
+					}},
+					"deployments": []interface{}{map[string]interface{}{
+						"name":  "foo-operator",
+						"label": map[string]interface{}{"app": "foo"},
+						"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+							"serviceAccountName": "foo-operator",
+							"containers":         []interface{}{map[string]interface{}{"name": "manager", "image": "foo:v1"}},
+						}}},
+					}},
+				}}},
+			}},
+			{Object: map[string]interface{}{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind":       "CustomResourceDefinition",
+				"metadata":   map[string]interface{}{"name": "foos.foo.io"},
+			}},
+		}
+	})
+
+	kinds := func(objects []unstructured.Unstructured) []string {
+		var kinds []string
+		for _, obj := range objects {
+			kinds = append(kinds, obj.GetKind())
+		}
+		return kinds
+	}
+
+	It("installs an operator watching all namespaces in the install namespace", func() {
+		rendered, err := render.RegistryV1(objects, "foo", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(rendered)).To(Equal([]string{"Namespace", "ServiceAccount", "ClusterRole", "ClusterRole", "ClusterRoleBinding", "ClusterRoleBinding", "CustomResourceDefinition", "Deployment"}))
+		Expect(rendered[0].GetName()).To(Equal("foo"))
+		Expect(rendered[1].GetNamespace()).To(Equal("foo"))
+		Expect(rendered[1].GetName()).To(Equal("foo-operator"))
+
+		deployment := rendered[7]
+		Expect(deployment.GetNamespace()).To(Equal("foo"))
+		Expect(deployment.GetName()).To(Equal("foo-operator"))
+		Expect(deployment.GetLabels()).To(Equal(map[string]string{"app": "foo"}))
+		Expect(deployment.GetAnnotations()).To(HaveKeyWithValue("olm.targetNamespaces", ""))
+		Expect(deployment.GetAnnotations()).To(HaveKeyWithValue("operatorframework.io/suggested-namespace", "foo-system"))
+		_, found, _ := unstructured.NestedFieldNoCopy(deployment.Object, "spec", "template", "metadata", "annotations")
+		Expect(found).To(BeFalse())
+
+		subjects, _, _ := unstructured.NestedSlice(rendered[4].Object, "subjects")
+		Expect(subjects).To(ConsistOf(map[string]interface{}{"kind": "ServiceAccount", "name": "foo-operator", "namespace": "foo"}))
+	})
+
+	It("grants the namespaced permissions of an operator watching some namespaces in those namespaces", func() {
+		rendered, err := render.RegistryV1(objects, "foo", []string{"bar"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds(rendered)).To(Equal([]string{"Namespace", "ServiceAccount", "Role", "Role", "RoleBinding", "RoleBinding", "ClusterRole", "ClusterRoleBinding", "CustomResourceDefinition", "Deployment"}))
+		Expect([]string{rendered[2].GetNamespace(), rendered[3].GetNamespace()}).To(Equal([]string{"bar", "foo"}))
+		Expect(rendered[9].GetAnnotations()).To(HaveKeyWithValue("olm.targetNamespaces", "bar"))
+		podAnnotations, _, _ := unstructured.NestedStringMap(rendered[9].Object, "spec", "template", "metadata", "annotations")
+		Expect(podAnnotations).To(HaveKeyWithValue("olm.targetNamespaces", "bar"))
+	})
+
+	It("names the RBAC the way the registry provisioner of rukpak does", func() {
+		rendered, err := render.RegistryV1(objects, "foo", nil)
+		Expect(err).NotTo(HaveOccurred())
+		names := map[string]string{}
+		for _, obj := range rendered[2:4] {
+			rules, _, _ := unstructured.NestedSlice(obj.Object, "rules")
+			names[obj.GetName()] = rules[0].(map[string]interface{})["resources"].([]interface{})[0].(string)
+		}
+		Expect(names).To(Equal(map[string]string{
+			"foo.v1.0.0-foo-operator-657b77f6b7": "foos",
+			"foo.v1.0.0-foo-operator-74ccfcb8f5": "configmaps",
+		}))
+
+		By("keeping the names when the permissions are granted in the watched namespaces")
+		rendered, err = render.RegistryV1(objects, "foo", []string{"bar"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect([]string{rendered[2].GetName(), rendered[6].GetName()}).To(Equal([]string{"foo.v1.0.0-foo-operator-74ccfcb8f5", "foo.v1.0.0-foo-operator-657b77f6b7"}))
+	})
+
+	It("renders the same objects each time", func() {
+		first, err := render.RegistryV1(objects, "foo", []string{"bar", "baz"})
+		Expect(err).NotTo(HaveOccurred())
+		second, err := render.RegistryV1(objects, "foo", []string{"bar", "baz"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).To(Equal(first))
+	})
+
+	It("does not modify the given objects", func() {
+		original := []unstructured.Unstructured{*objects[0].DeepCopy(), *objects[1].DeepCopy()}
+		_, err := render.RegistryV1(objects, "foo", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects).To(Equal(original))
+	})
+
+	It("fails without a ClusterServiceVersion", func() {
+		_, err := render.RegistryV1(objects[1:], "foo", nil)
+		Expect(err).To(MatchError("the bundle has no ClusterServiceVersion"))
+	})
+
+	It("fails for webhooks, which are not supported", func() {
+		Expect(unstructured.SetNestedSlice(objects[0].Object, []interface{}{map[string]interface{}{"type": "ValidatingAdmissionWebhook"}}, "spec", "webhookdefinitions")).To(Succeed())
+		_, err := render.RegistryV1(objects, "foo", nil)
+		Expect(err).To(MatchError(ContainSubstring("webhookDefinitions are not supported")))
+	})
+})
@@ -11,6 +11,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	catalogd "github.com/operator-framework/catalogd/pkg/apis/core/v1beta1"
+
+	olmentity "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
 )
 
 // catalogdEntitySource is a source for(/collection of) deppy defined input.Entity, built from content
@@ -79,14 +81,26 @@ func getEntities(ctx context.Context, client client.Client) (input.EntityList, e
 	}
 	for _, bundle := range bundleMetadatas.Items {
 		props := map[string]string{}
+		listProps := map[string][]json.RawMessage{}
 
 		for _, prop := range bundle.Spec.Properties {
 			switch prop.Type {
-			case property.TypePackage:
+			case property.TypePackage, olmentity.PropertyCSVMetadata:
 				// this is already a json marshalled object, so it doesn't need to be marshalled
 				// like the other ones
-				props[property.TypePackage] = string(prop.Value)
+				props[prop.Type] = string(prop.Value)
+			case property.TypeGVK, property.TypeGVKRequired, property.TypePackageRequired, property.TypeBundleObject, olmentity.PropertyHealthCheck:
+				// bundles can declare any number of these, but entities carry them
+				// as a single list
+				listProps[prop.Type] = append(listProps[prop.Type], prop.Value)
+			}
+		}
+		for propType, values := range listProps {
+			listValue, err := json.Marshal(values)
+			if err != nil {
+				return nil, err
 			}
+			props[propType] = string(listValue)
 		}
 
 		imgValue, err := json.Marshal(bundle.Spec.Image)
@@ -94,6 +108,11 @@ func getEntities(ctx context.Context, client client.Client) (input.EntityList, e
 			return nil, err
 		}
 		props["olm.bundle.path"] = string(imgValue)
+		catalogNameValue, err := json.Marshal(bundle.Spec.Catalog.Name)
+		if err != nil {
+			return nil, err
+		}
+		props[olmentity.PropertyCatalogName] = string(catalogNameValue)
 		catalogScopedPkgName := fmt.Sprintf("%s-%s", bundle.Spec.Catalog.Name, bundle.Spec.Package)
 		bundlePkg := packageMetdatas[catalogScopedPkgName]
 		for _, ch := range bundlePkg.Spec.Channels {
@@ -102,6 +121,8 @@ func getEntities(ctx context.Context, client client.Client) (input.EntityList, e
 				if catalogScopedEntryName == bundle.Name {
 					channelValue, _ := json.Marshal(property.Channel{ChannelName: ch.Name, Priority: 0})
 					props[property.TypeChannel] = string(channelValue)
+					bundleNameValue, _ := json.Marshal(b.Name)
+					props[olmentity.PropertyBundleName] = string(bundleNameValue)
 					entity := input.Entity{
 						ID:         deppy.IdentifierFromString(fmt.Sprintf("%s%s%s", bundle.Name, bundle.Spec.Package, ch.Name)),
 						Properties: props,
@@ -2,25 +2,60 @@ package resolution
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/blang/semver/v4"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
 	"github.com/operator-framework/deppy/pkg/deppy/solver"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/crd_constraints"
+	olmentity "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/olm"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/util/predicates"
+	entitysort "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/util/sort"
 )
 
 type OperatorResolver struct {
 	entitySource input.EntitySource
 	client       client.Client
+	discovery    discovery.ServerResourcesInterface
+
+	mu sync.Mutex
+	// crdsFingerprint identifies the CRDs of the cluster when the discovery client was last invalidated
+	crdsFingerprint string
+}
+
+type OperatorResolverOption func(*OperatorResolver)
+
+// WithDiscovery makes the resolver consider the gvks served by the cluster as provided, so that
+// bundles requiring them do not depend on any other bundle for them. The discovery client should
+// cache its results, e.g. with memory.NewMemCacheClient, as they are needed by each resolution.
+func WithDiscovery(discovery discovery.ServerResourcesInterface) OperatorResolverOption {
+	return func(o *OperatorResolver) {
+		o.discovery = discovery
+	}
 }
 
-func NewOperatorResolver(client client.Client, entitySource input.EntitySource) *OperatorResolver {
-	return &OperatorResolver{
+func NewOperatorResolver(client client.Client, entitySource input.EntitySource, options ...OperatorResolverOption) *OperatorResolver {
+	o := &OperatorResolver{
 		entitySource: entitySource,
 		client:       client,
 	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
 }
 
 func (o *OperatorResolver) Resolve(ctx context.Context) (*solver.Solution, error) {
@@ -32,8 +67,36 @@ func (o *OperatorResolver) Resolve(ctx context.Context) (*solver.Solution, error
 		return &solver.Solution{}, nil
 	}
 
-	olmVariableSource := olm.NewOLMVariableSource(operatorList.Items...)
-	deppySolver := solver.NewDeppySolver(o.entitySource, olmVariableSource)
+	// CRDs installed by anything but operator-controller conflict with the bundles providing them,
+	// their specs telling which kinds they provide
+	crdList := apiextensionsv1.CustomResourceDefinitionList{}
+	if err := o.client.List(ctx, &crdList); err != nil {
+		return nil, err
+	}
+	operatorNames := sets.NewString()
+	for _, operator := range operatorList.Items {
+		operatorNames.Insert(operator.GetName())
+	}
+
+	servedGVKs, err := o.servedGVKs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the kinds of the CRDs operator-controller installed are provided by the bundles that installed
+	// them, which must remain in the solution for as long as they are required
+	for i := range crdList.Items {
+		crd := &crdList.Items[i]
+		if !crd_constraints.InstalledByOperator(crd, operatorNames) {
+			continue
+		}
+		for _, version := range crd.Spec.Versions {
+			delete(servedGVKs, olmentity.GVK{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind})
+		}
+	}
+
+	olmVariableSource := olm.NewOLMVariableSourceWithServedGVKs(servedGVKs, operatorList.Items...)
+	variableSource := crd_constraints.NewForeignCRDConstraintsVariableSource(olmVariableSource, crdList.Items, operatorNames.List()...)
+	deppySolver := solver.NewDeppySolver(o.entitySource, variableSource)
 
 	solution, err := deppySolver.Solve(ctx)
 	if err != nil {
@@ -41,3 +104,99 @@ func (o *OperatorResolver) Resolve(ctx context.Context) (*solver.Solution, error
 	}
 	return solution, nil
 }
+
+// servedGVKs returns the gvks served by the cluster, or none if the resolver has no discovery client.
+// A cached discovery client is invalidated when the CRDs of the cluster change, which is when the
+// gvks it serves change.
+func (o *OperatorResolver) servedGVKs(ctx context.Context) (bundles_and_dependencies.ServedGVKs, error) {
+	if o.discovery == nil {
+		return nil, nil
+	}
+	if cached, ok := o.discovery.(discovery.CachedDiscoveryInterface); ok {
+		// the metadata of the CRDs is enough to tell whether they changed
+		crds := &metav1.PartialObjectMetadataList{}
+		crds.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinitionList"))
+		if err := o.client.List(ctx, crds); err != nil {
+			return nil, err
+		}
+		o.mu.Lock()
+		if fingerprint := crdsFingerprint(crds.Items); fingerprint != o.crdsFingerprint {
+			cached.Invalidate()
+			o.crdsFingerprint = fingerprint
+		}
+		o.mu.Unlock()
+	}
+	_, resourceLists, err := o.discovery.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("failed to discover the APIs served by the cluster: %w", err)
+	}
+	// the groups that could be discovered are still served, even if others failed
+	servedGVKs := bundles_and_dependencies.ServedGVKs{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				// subresources do not define a kind of their own
+				continue
+			}
+			servedGVKs[olmentity.GVK{Group: gv.Group, Version: gv.Version, Kind: resource.Kind}] = struct{}{}
+		}
+	}
+	return servedGVKs, nil
+}
+
+// crdsFingerprint identifies the specs of the given CRDs.
+func crdsFingerprint(crds []metav1.PartialObjectMetadata) string {
+	specs := make([]string, 0, len(crds))
+	for _, crd := range crds {
+		specs = append(specs, fmt.Sprintf("%s/%s/%d", crd.GetName(), crd.GetUID(), crd.GetGeneration()))
+	}
+	sort.Strings(specs)
+	return strings.Join(specs, ",")
+}
+
+// BundleByPath returns the bundle of the given package with the given path. If the bundle is
+// available in several channels, any of them is returned.
+func (o *OperatorResolver) BundleByPath(ctx context.Context, packageName string, bundlePath string) (*olmentity.BundleEntity, error) {
+	entities, err := o.entitySource.Filter(ctx, input.And(predicates.WithPackageName(packageName), predicates.WithBundlePath(bundlePath)))
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("bundle %q of package %q not found", bundlePath, packageName)
+	}
+	return olmentity.NewBundleEntity(&entities[0]), nil
+}
+
+// AvailableUpgrades returns the bundles of the given package that are newer than the bundle
+// with the given path, regardless of any constraint set on the Operators. A bundle available
+// in several channels is returned once per channel. The bundles are ordered by channel and
+// then from the highest to the lowest version.
+func (o *OperatorResolver) AvailableUpgrades(ctx context.Context, packageName string, bundlePath string) ([]*olmentity.BundleEntity, error) {
+	current, err := o.BundleByPath(ctx, packageName, bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	currentVersion, err := current.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	newerThanCurrent := func(v semver.Version) bool {
+		return v.GT(*currentVersion)
+	}
+	newerEntities, err := o.entitySource.Filter(ctx, input.And(predicates.WithPackageName(packageName), predicates.InSemverRange(newerThanCurrent)))
+	if err != nil {
+		return nil, err
+	}
+	newerEntities = newerEntities.Sort(entitysort.ByChannelAndVersion)
+
+	upgrades := make([]*olmentity.BundleEntity, 0, len(newerEntities))
+	for i := range newerEntities {
+		upgrades = append(upgrades, olmentity.NewBundleEntity(&newerEntities[i]))
+	}
+	return upgrades, nil
+}
@@ -9,8 +9,12 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/operator-framework/deppy/pkg/deppy"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -28,6 +32,9 @@ func FakeClient(objects ...client.Object) client.Client {
 	if err := v1alpha1.AddToScheme(scheme); err != nil {
 		panic(fmt.Sprintf("error creating fake client: %s", err))
 	}
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		panic(fmt.Sprintf("error creating fake client: %s", err))
+	}
 	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
 }
 
@@ -50,6 +57,12 @@ var testEntityCache = map[deppy.Identifier]input.Entity{
 		"olm.gvk":         "[{\"group\":\"foo.io\",\"kind\":\"Foo\",\"version\":\"v1\"}]",
 		"olm.package":     "{\"packageName\":\"packageA\",\"version\":\"2.0.0\"}",
 	}),
+	"operatorhub/packageB/1.0.0": *input.NewEntity("operatorhub/packageB/1.0.0", map[string]string{
+		"olm.bundle.path":  `"foo.io/packageB/packageB:v1.0.0"`,
+		"olm.channel":      "{\"channelName\":\"stable\",\"priority\":0}",
+		"olm.gvk.required": "[{\"group\":\"foo.io\",\"kind\":\"Foo\",\"version\":\"v1\"}]",
+		"olm.package":      "{\"packageName\":\"packageB\",\"version\":\"1.0.0\"}",
+	}),
 }
 
 var _ = Describe("OperatorResolver", func() {
@@ -116,6 +129,144 @@ var _ = Describe("OperatorResolver", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should not resolve bundles that provide a CRD installed by something else", func() {
+		resources := []client.Object{
+			&v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "packageA",
+				},
+				Spec: v1alpha1.OperatorSpec{
+					PackageName: "packageA",
+				},
+			},
+			fooCRD(map[string]string{"app.kubernetes.io/managed-by": "Helm"}),
+		}
+		client := FakeClient(resources...)
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(client, entitySource)
+		solution, err := resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		err = solution.Error()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("CustomResourceDefinition foos.foo.io is owned by Helm"))
+		Expect(err.Error()).To(ContainSubstring("CustomResourceDefinition foos.foo.io conflicts with package packageA at version 2.0.0"))
+	})
+
+	It("should resolve bundles that provide a CRD installed for an Operator", func() {
+		resources := []client.Object{
+			&v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "packageA",
+				},
+				Spec: v1alpha1.OperatorSpec{
+					PackageName: "packageA",
+				},
+			},
+			fooCRD(map[string]string{"core.rukpak.io/owner-kind": "BundleDeployment", "core.rukpak.io/owner-name": "packageA"}),
+		}
+		client := FakeClient(resources...)
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(client, entitySource)
+		solution, err := resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(solution.IsSelected("operatorhub/packageA/2.0.0")).To(BeTrue())
+	})
+
+	It("should satisfy required gvks with the APIs served by the cluster", func() {
+		resources := []client.Object{
+			&v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "packageB",
+				},
+				Spec: v1alpha1.OperatorSpec{
+					PackageName: "packageB",
+				},
+			},
+		}
+		client := FakeClient(resources...)
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(client, entitySource)
+		solution, err := resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(solution.IsSelected("operatorhub/packageB/1.0.0")).To(BeTrue())
+		Expect(solution.IsSelected("operatorhub/packageA/2.0.0")).To(BeTrue())
+
+		discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+			GroupVersion: "foo.io/v1",
+			APIResources: []metav1.APIResource{{Name: "foos", Kind: "Foo"}, {Name: "foos/status", Kind: "Foo"}},
+		}}}}
+		resolver = resolution.NewOperatorResolver(client, entitySource, resolution.WithDiscovery(discovery))
+		solution, err = resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(solution.IsSelected("operatorhub/packageB/1.0.0")).To(BeTrue())
+		Expect(solution.IsSelected("operatorhub/packageA/2.0.0")).To(BeFalse())
+	})
+
+	It("should not satisfy required gvks with the APIs of CRDs installed for an Operator", func() {
+		crd := fooCRD(map[string]string{"core.rukpak.io/owner-kind": "BundleDeployment", "core.rukpak.io/owner-name": "packageA"})
+		crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}}
+		resources := []client.Object{
+			&v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "packageA",
+				},
+				Spec: v1alpha1.OperatorSpec{
+					PackageName: "packageA",
+				},
+			},
+			&v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "packageB",
+				},
+				Spec: v1alpha1.OperatorSpec{
+					PackageName: "packageB",
+				},
+			},
+			crd,
+		}
+		client := FakeClient(resources...)
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+			GroupVersion: "foo.io/v1",
+			APIResources: []metav1.APIResource{{Name: "foos", Kind: "Foo"}},
+		}}}}
+		resolver := resolution.NewOperatorResolver(client, entitySource, resolution.WithDiscovery(discovery))
+		solution, err := resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(solution.IsSelected("operatorhub/packageB/1.0.0")).To(BeTrue())
+		Expect(solution.IsSelected("operatorhub/packageA/2.0.0")).To(BeTrue())
+	})
+
+	It("should invalidate a cached discovery client only when the CRDs change", func() {
+		resources := []client.Object{
+			&v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "packageB",
+				},
+				Spec: v1alpha1.OperatorSpec{
+					PackageName: "packageB",
+				},
+			},
+		}
+		client := FakeClient(resources...)
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		discovery := &countingCachedDiscovery{FakeDiscovery: &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}}
+		resolver := resolution.NewOperatorResolver(client, entitySource, resolution.WithDiscovery(discovery))
+		_, err := resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(discovery.invalidations).To(BeZero())
+
+		Expect(client.Create(context.Background(), fooCRD(nil))).To(Succeed())
+		_, err = resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(discovery.invalidations).To(Equal(1))
+		_, err = resolver.Resolve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(discovery.invalidations).To(Equal(1))
+	})
+
 	It("should return an error if the client throws an error", func() {
 		client := NewFailClientWithError(fmt.Errorf("something bad happened"))
 		entitySource := input.NewCacheQuerier(testEntityCache)
@@ -126,6 +277,73 @@ var _ = Describe("OperatorResolver", func() {
 	})
 })
 
+var _ = Describe("OperatorResolver BundleByPath", func() {
+	It("should return the bundle with the given path", func() {
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(FakeClient(), entitySource)
+		bundle, err := resolver.BundleByPath(context.Background(), "prometheus", "quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bundle.Identifier()).To(Equal(deppy.IdentifierFromString("operatorhub/prometheus/0.37.0")))
+	})
+
+	It("should return an error if the bundle is not found", func() {
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(FakeClient(), entitySource)
+		bundle, err := resolver.BundleByPath(context.Background(), "packageA", "quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35")
+		Expect(bundle).To(BeNil())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("OperatorResolver AvailableUpgrades", func() {
+	It("should return the bundles of the package newer than the given one", func() {
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(FakeClient(), entitySource)
+		upgrades, err := resolver.AvailableUpgrades(context.Background(), "prometheus", "quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(upgrades).To(HaveLen(1))
+		Expect(upgrades[0].Identifier()).To(Equal(deppy.IdentifierFromString("operatorhub/prometheus/0.47.0")))
+	})
+
+	It("should not return any bundle for the latest version", func() {
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(FakeClient(), entitySource)
+		upgrades, err := resolver.AvailableUpgrades(context.Background(), "prometheus", "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(upgrades).To(BeEmpty())
+	})
+
+	It("should return an error if the given bundle is not found", func() {
+		entitySource := input.NewCacheQuerier(testEntityCache)
+		resolver := resolution.NewOperatorResolver(FakeClient(), entitySource)
+		upgrades, err := resolver.AvailableUpgrades(context.Background(), "prometheus", "quay.io/operatorhubio/prometheus:unknown")
+		Expect(upgrades).To(BeNil())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the entity source throws an error", func() {
+		resolver := resolution.NewOperatorResolver(FakeClient(), FailEntitySource{})
+		upgrades, err := resolver.AvailableUpgrades(context.Background(), "prometheus", "quay.io/operatorhubio/prometheus:unknown")
+		Expect(upgrades).To(BeNil())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ discovery.CachedDiscoveryInterface = &countingCachedDiscovery{}
+
+type countingCachedDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	invalidations int
+}
+
+func (c *countingCachedDiscovery) Fresh() bool {
+	return true
+}
+
+func (c *countingCachedDiscovery) Invalidate() {
+	c.invalidations++
+}
+
 var _ input.EntitySource = &FailEntitySource{}
 
 type FailEntitySource struct{}
@@ -187,3 +405,16 @@ func (f FailClient) Patch(ctx context.Context, obj client.Object, patch client.P
 func (f FailClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
 	return f.err
 }
+
+func fooCRD(labels map[string]string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "foos.foo.io",
+			Labels: labels,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "foo.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "foos", Kind: "Foo"},
+		},
+	}
+}
@@ -18,8 +18,9 @@ import (
 
 type BundleVariable struct {
 	*input.SimpleVariable
-	bundleEntity *olmentity.BundleEntity
-	dependencies []*olmentity.BundleEntity
+	bundleEntity        *olmentity.BundleEntity
+	dependencies        []*olmentity.BundleEntity
+	clusterProvidedGVKs []olmentity.GVK
 }
 
 func (b *BundleVariable) BundleEntity() *olmentity.BundleEntity {
@@ -30,7 +31,18 @@ func (b *BundleVariable) Dependencies() []*olmentity.BundleEntity {
 	return b.dependencies
 }
 
+// ClusterProvidedGVKs returns the gvks required by the bundle that the cluster already serves.
+func (b *BundleVariable) ClusterProvidedGVKs() []olmentity.GVK {
+	return b.clusterProvidedGVKs
+}
+
 func NewBundleVariable(bundleEntity *olmentity.BundleEntity, dependencyBundleEntities []*olmentity.BundleEntity) *BundleVariable {
+	return NewBundleVariableWithClusterProvidedGVKs(bundleEntity, dependencyBundleEntities, nil)
+}
+
+// NewBundleVariableWithClusterProvidedGVKs creates a new bundle variable for a bundle whose required
+// gvks are partly provided by the cluster rather than by the given dependencies.
+func NewBundleVariableWithClusterProvidedGVKs(bundleEntity *olmentity.BundleEntity, dependencyBundleEntities []*olmentity.BundleEntity, clusterProvidedGVKs []olmentity.GVK) *BundleVariable {
 	var dependencyIDs []deppy.Identifier
 	for _, bundle := range dependencyBundleEntities {
 		dependencyIDs = append(dependencyIDs, bundle.ID)
@@ -40,21 +52,34 @@ func NewBundleVariable(bundleEntity *olmentity.BundleEntity, dependencyBundleEnt
 		constraints = append(constraints, constraint.Dependency(dependencyIDs...))
 	}
 	return &BundleVariable{
-		SimpleVariable: input.NewSimpleVariable(bundleEntity.ID, constraints...),
-		bundleEntity:   bundleEntity,
-		dependencies:   dependencyBundleEntities,
+		SimpleVariable:      input.NewSimpleVariable(bundleEntity.ID, constraints...),
+		bundleEntity:        bundleEntity,
+		dependencies:        dependencyBundleEntities,
+		clusterProvidedGVKs: clusterProvidedGVKs,
 	}
 }
 
+// ServedGVKs is the set of gvks served by the cluster. They act as virtual providers: a required gvk
+// that the cluster already serves is satisfied without installing any bundle.
+type ServedGVKs map[olmentity.GVK]struct{}
+
 var _ input.VariableSource = &BundlesAndDepsVariableSource{}
 
 type BundlesAndDepsVariableSource struct {
 	variableSources []input.VariableSource
+	servedGVKs      ServedGVKs
 }
 
 func NewBundlesAndDepsVariableSource(inputVariableSources ...input.VariableSource) *BundlesAndDepsVariableSource {
+	return NewBundlesAndDepsVariableSourceWithServedGVKs(nil, inputVariableSources...)
+}
+
+// NewBundlesAndDepsVariableSourceWithServedGVKs creates a new BundlesAndDepsVariableSource that considers
+// the gvks served by the cluster as provided.
+func NewBundlesAndDepsVariableSourceWithServedGVKs(servedGVKs ServedGVKs, inputVariableSources ...input.VariableSource) *BundlesAndDepsVariableSource {
 	return &BundlesAndDepsVariableSource{
 		variableSources: inputVariableSources,
+		servedGVKs:      servedGVKs,
 	}
 }
 
@@ -93,7 +118,7 @@ func (b *BundlesAndDepsVariableSource) GetVariables(ctx context.Context, entityS
 		visited[head.ID] = struct{}{}
 
 		// get bundle dependencies
-		dependencyEntityBundles, err := b.getEntityDependencies(ctx, head, entitySource)
+		dependencyEntityBundles, clusterProvidedGVKs, err := b.getEntityDependencies(ctx, head, entitySource)
 		if err != nil {
 			return nil, fmt.Errorf("could not determine dependencies for entity with id '%s': %w", head.ID, err)
 		}
@@ -102,14 +127,15 @@ func (b *BundlesAndDepsVariableSource) GetVariables(ctx context.Context, entityS
 		bundleEntityQueue = append(bundleEntityQueue, dependencyEntityBundles...)
 
 		// create variable
-		variables = append(variables, NewBundleVariable(head, dependencyEntityBundles))
+		variables = append(variables, NewBundleVariableWithClusterProvidedGVKs(head, dependencyEntityBundles, clusterProvidedGVKs))
 	}
 
 	return variables, nil
 }
 
-func (b *BundlesAndDepsVariableSource) getEntityDependencies(ctx context.Context, bundleEntity *olmentity.BundleEntity, entitySource input.EntitySource) ([]*olmentity.BundleEntity, error) {
+func (b *BundlesAndDepsVariableSource) getEntityDependencies(ctx context.Context, bundleEntity *olmentity.BundleEntity, entitySource input.EntitySource) ([]*olmentity.BundleEntity, []olmentity.GVK, error) {
 	var dependencies []*olmentity.BundleEntity
+	var clusterProvidedGVKs []olmentity.GVK
 	added := map[deppy.Identifier]struct{}{}
 
 	// gather required package dependencies
@@ -118,14 +144,14 @@ func (b *BundlesAndDepsVariableSource) getEntityDependencies(ctx context.Context
 	for _, requiredPackage := range requiredPackages {
 		semverRange, err := semver.ParseRange(requiredPackage.VersionRange)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		packageDependencyBundles, err := entitySource.Filter(ctx, input.And(predicates.WithPackageName(requiredPackage.PackageName), predicates.InSemverRange(semverRange)))
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if len(packageDependencyBundles) == 0 {
-			return nil, fmt.Errorf("could not find package dependencies for bundle '%s'", bundleEntity.ID)
+			return nil, nil, fmt.Errorf("could not find package dependencies for bundle '%s'", bundleEntity.ID)
 		}
 		for i := 0; i < len(packageDependencyBundles); i++ {
 			entity := packageDependencyBundles[i]
@@ -141,12 +167,17 @@ func (b *BundlesAndDepsVariableSource) getEntityDependencies(ctx context.Context
 	gvkDependencies, _ := bundleEntity.RequiredGVKs()
 	for i := 0; i < len(gvkDependencies); i++ {
 		providedGvk := gvkDependencies[i].AsGVK()
+		if _, ok := b.servedGVKs[providedGvk]; ok {
+			// the cluster already serves the gvk, nothing needs to be installed to provide it
+			clusterProvidedGVKs = append(clusterProvidedGVKs, providedGvk)
+			continue
+		}
 		gvkDependencyBundles, err := entitySource.Filter(ctx, predicates.ProvidesGVK(&providedGvk))
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if len(gvkDependencyBundles) == 0 {
-			return nil, fmt.Errorf("could not find gvk dependencies for bundle '%s'", bundleEntity.ID)
+			return nil, nil, fmt.Errorf("could not find gvk dependencies for bundle '%s'", bundleEntity.ID)
 		}
 		for i := 0; i < len(gvkDependencyBundles); i++ {
 			entity := gvkDependencyBundles[i]
@@ -162,5 +193,5 @@ func (b *BundlesAndDepsVariableSource) getEntityDependencies(ctx context.Context
 		return entitysort.ByChannelAndVersion(dependencies[i].Entity, dependencies[j].Entity)
 	})
 
-	return dependencies, nil
+	return dependencies, clusterProvidedGVKs, nil
 }
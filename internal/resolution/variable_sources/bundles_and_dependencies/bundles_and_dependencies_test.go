@@ -233,6 +233,37 @@ var _ = Describe("BundlesAndDepsVariableSource", func() {
 		})))
 	})
 
+	It("should satisfy required gvks served by the cluster without dependencies", func() {
+		bdvs = bundles_and_dependencies.NewBundlesAndDepsVariableSourceWithServedGVKs(
+			bundles_and_dependencies.ServedGVKs{{Group: "foo.io", Version: "v1", Kind: "Foo"}: {}},
+			&MockRequiredPackageSource{
+				ResultSet: []deppy.Variable{
+					required_package.NewRequiredPackageVariable("test-package", []*olmentity.BundleEntity{
+						olmentity.NewBundleEntity(input.NewEntity("bundle-1", map[string]string{
+							property.TypePackage:     `{"packageName": "test-package", "version": "1.0.0"}`,
+							property.TypeChannel:     `{"channelName":"stable","priority":0}`,
+							property.TypeGVKRequired: `[{"group":"foo.io","kind":"Foo","version":"v1"}]`,
+						})),
+					}),
+				},
+			},
+		)
+		variables, err := bdvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+
+		var bundleVariables []*bundles_and_dependencies.BundleVariable
+		for _, variable := range variables {
+			switch v := variable.(type) {
+			case *bundles_and_dependencies.BundleVariable:
+				bundleVariables = append(bundleVariables, v)
+			}
+		}
+		Expect(bundleVariables).To(WithTransform(CollectBundleVariableIDs, Equal([]string{"bundle-1"})))
+		Expect(bundleVariables[0].Dependencies()).To(BeEmpty())
+		Expect(bundleVariables[0].Constraints()).To(BeEmpty())
+		Expect(bundleVariables[0].ClusterProvidedGVKs()).To(Equal([]olmentity.GVK{{Group: "foo.io", Version: "v1", Kind: "Foo"}}))
+	})
+
 	It("should return error if dependencies not found", func() {
 		mockEntitySource = input.NewCacheQuerier(map[deppy.Identifier]input.Entity{})
 		_, err := bdvs.GetVariables(context.TODO(), mockEntitySource)
@@ -3,10 +3,13 @@ package crd_constraints
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/operator-framework/deppy/pkg/deppy"
 	"github.com/operator-framework/deppy/pkg/deppy/constraint"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
 	olmentity "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
@@ -27,13 +30,29 @@ func NewBundleUniquenessVariable(id deppy.Identifier, atMostIDs ...deppy.Identif
 	}
 }
 
+// NewCRDUniquenessVariable creates a new variable that instructs the resolver to choose at most a single bundle
+// from the input 'atMostIDs', which all provide the CRD of the given group and kind. Bundles providing different
+// versions of the same CRD would overwrite each other's CRD object.
+func NewCRDUniquenessVariable(groupKind schema.GroupKind, atMostIDs ...deppy.Identifier) *BundleUniquenessVariable {
+	id := deppy.IdentifierFromString(fmt.Sprintf("%s crd uniqueness", groupKind))
+	return &BundleUniquenessVariable{
+		SimpleVariable: input.NewSimpleVariable(id, constraint.NewUserFriendlyConstraint(constraint.AtMost(1, atMostIDs...), func(_ deppy.Constraint, _ deppy.Identifier) string {
+			ids := make([]string, len(atMostIDs))
+			for i, atMostID := range atMostIDs {
+				ids[i] = atMostID.String()
+			}
+			return fmt.Sprintf("bundles %s all provide the CustomResourceDefinition of %s, so at most one of them can be installed", strings.Join(ids, ", "), groupKind)
+		})),
+	}
+}
+
 var _ input.VariableSource = &CRDUniquenessConstraintsVariableSource{}
 
 // CRDUniquenessConstraintsVariableSource produces variables that constraint the solution to
 // 1. at most 1 bundle per package
-// 2. at most 1 bundle per gvk (provided by the bundle)
-// these variables guarantee that no two operators provide the same gvk and no two version of
-// the same operator are running at the same time.
+// 2. at most 1 bundle per CRD, identified by the group and kind of the gvks provided by the bundle
+// these variables guarantee that no two operators provide the same CRD, whatever its versions, and no
+// two version of the same operator are running at the same time.
 // This variable source does not itself reach out to its entitySource. It produces its variables
 // by searching for BundleVariables that are produced by its 'inputVariableSource' and working out
 // which bundles correspond to which package and which gvks are provided by which bundle
@@ -60,7 +79,7 @@ func (g *CRDUniquenessConstraintsVariableSource) GetVariables(ctx context.Contex
 	//                   not all packages will necessarily export a CRD
 
 	pkgToBundleMap := map[string]map[deppy.Identifier]struct{}{}
-	gvkToBundleMap := map[string]map[deppy.Identifier]struct{}{}
+	crdToBundleMap := map[schema.GroupKind]map[deppy.Identifier]struct{}{}
 	for _, variable := range variables {
 		switch v := variable.(type) {
 		case *bundles_and_dependencies.BundleVariable:
@@ -84,11 +103,12 @@ func (g *CRDUniquenessConstraintsVariableSource) GetVariables(ctx context.Contex
 					return nil, fmt.Errorf("error creating global constraints: %w", err)
 				}
 				for i := 0; i < len(exportedGVKs); i++ {
-					gvk := exportedGVKs[i].String()
-					if _, ok := gvkToBundleMap[gvk]; !ok {
-						gvkToBundleMap[gvk] = map[deppy.Identifier]struct{}{}
+					// different versions of a kind are served by the same CRD
+					groupKind := schema.GroupKind{Group: exportedGVKs[i].Group, Kind: exportedGVKs[i].Kind}
+					if _, ok := crdToBundleMap[groupKind]; !ok {
+						crdToBundleMap[groupKind] = map[deppy.Identifier]struct{}{}
 					}
-					gvkToBundleMap[gvk][bundleEntity.ID] = struct{}{}
+					crdToBundleMap[groupKind][bundleEntity.ID] = struct{}{}
 				}
 			}
 		}
@@ -104,13 +124,15 @@ func (g *CRDUniquenessConstraintsVariableSource) GetVariables(ctx context.Contex
 		variables = append(variables, NewBundleUniquenessVariable(varID, bundleIDs...))
 	}
 
-	for gvk, bundleIDMap := range gvkToBundleMap {
+	for groupKind, bundleIDMap := range crdToBundleMap {
 		var bundleIDs []deppy.Identifier
 		for bundleID := range bundleIDMap {
 			bundleIDs = append(bundleIDs, bundleID)
 		}
-		varID := deppy.IdentifierFromString(fmt.Sprintf("%s gvk uniqueness", gvk))
-		variables = append(variables, NewBundleUniquenessVariable(varID, bundleIDs...))
+		sort.Slice(bundleIDs, func(i, j int) bool {
+			return bundleIDs[i] < bundleIDs[j]
+		})
+		variables = append(variables, NewCRDUniquenessVariable(groupKind, bundleIDs...))
 	}
 
 	return variables, nil
@@ -11,6 +11,7 @@ import (
 	"github.com/operator-framework/deppy/pkg/deppy/constraint"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
 	"github.com/operator-framework/operator-registry/alpha/property"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/crd_constraints"
@@ -45,6 +46,15 @@ var _ = Describe("BundleUniquenessVariable", func() {
 	})
 })
 
+var _ = Describe("CRDUniquenessVariable", func() {
+	It("should permit at most one of the bundles providing the CRD", func() {
+		variable := crd_constraints.NewCRDUniquenessVariable(schema.GroupKind{Group: "foo.io", Kind: "Foo"}, "bundle-1", "bundle-2")
+		Expect(variable.Identifier()).To(Equal(deppy.IdentifierFromString("Foo.foo.io crd uniqueness")))
+		Expect(variable.Constraints()).To(HaveLen(1))
+		Expect(variable.Constraints()[0].String(variable.Identifier())).To(Equal("bundles bundle-1, bundle-2 all provide the CustomResourceDefinition of Foo.foo.io, so at most one of them can be installed"))
+	})
+})
+
 var bundleSet = map[deppy.Identifier]*input.Entity{
 	// required package bundles
 	"bundle-1": input.NewEntity("bundle-1", map[string]string{
@@ -255,14 +265,38 @@ var _ = Describe("CRDUniquenessConstraintsVariableSource", func() {
 			"test-package package uniqueness",
 			"some-package package uniqueness",
 			"some-other-package package uniqueness",
-			`group:"buz.io" version:"v1" kind:"Buz" gvk uniqueness`,
-			`group:"bit.io" version:"v1" kind:"Bit" gvk uniqueness`,
-			`group:"fiz.io" version:"v1" kind:"Fiz" gvk uniqueness`,
-			`group:"foo.io" version:"v1" kind:"Foo" gvk uniqueness`,
-			`group:"bar.io" version:"v1" kind:"Bar" gvk uniqueness`,
+			"Buz.buz.io crd uniqueness",
+			"Bit.bit.io crd uniqueness",
+			"Fiz.fiz.io crd uniqueness",
+			"Foo.foo.io crd uniqueness",
+			"Bar.bar.io crd uniqueness",
 		})))
 	})
 
+	It("should make bundles providing different versions of the same CRD conflict", func() {
+		inputVariableSource.ResultSet = []deppy.Variable{
+			bundles_and_dependencies.NewBundleVariable(olmentity.NewBundleEntity(bundleSet["bundle-14"]), nil),
+			bundles_and_dependencies.NewBundleVariable(olmentity.NewBundleEntity(bundleSet["bundle-11"]), nil),
+		}
+		variables, err := crdConstraintVariableSource.GetVariables(ctx, entitySource)
+		Expect(err).ToNot(HaveOccurred())
+		var crdConstraintVariables []*crd_constraints.BundleUniquenessVariable
+		for _, variable := range variables {
+			switch v := variable.(type) {
+			case *crd_constraints.BundleUniquenessVariable:
+				crdConstraintVariables = append(crdConstraintVariables, v)
+			}
+		}
+		Expect(crdConstraintVariables).To(WithTransform(CollectGlobalConstraintVariableIDs, ConsistOf(
+			"test-package-2 package uniqueness",
+			"unrelated-package package uniqueness",
+			"Buz.buz.io crd uniqueness",
+		)))
+		Expect(constraintMessages(crdConstraintVariables[2])).To(Equal([]string{
+			"bundles bundle-11, bundle-14 all provide the CustomResourceDefinition of Buz.buz.io, so at most one of them can be installed",
+		}))
+	})
+
 	It("should return an error if input variable source returns an error", func() {
 		inputVariableSource = &MockInputVariableSource{Err: fmt.Errorf("error getting variables")}
 		crdConstraintVariableSource = crd_constraints.NewCRDUniquenessConstraintsVariableSource(inputVariableSource)
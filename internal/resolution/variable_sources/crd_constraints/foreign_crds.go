@@ -0,0 +1,188 @@
+package crd_constraints
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/deppy/pkg/deppy"
+	"github.com/operator-framework/deppy/pkg/deppy/constraint"
+	"github.com/operator-framework/deppy/pkg/deppy/input"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
+	olmentity "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+const (
+	// labels set by rukpak on the objects it installs for a BundleDeployment
+	rukpakOwnerKindLabel = "core.rukpak.io/owner-kind"
+	rukpakOwnerNameLabel = "core.rukpak.io/owner-name"
+
+	// labels and annotations set by other installers
+	managedByLabel            = "app.kubernetes.io/managed-by"
+	helmReleaseNameAnnotation = "meta.helm.sh/release-name"
+	helmReleaseNSAnnotation   = "meta.helm.sh/release-namespace"
+	olmOperatorLabelPrefix    = "operators.coreos.com/"
+)
+
+type ForeignCRDVariable struct {
+	*input.SimpleVariable
+}
+
+// NewForeignCRDVariable creates a new variable that instructs the resolver to choose none of the given
+// bundles, as they provide a kind defined by a CRD that exists on the cluster and is owned by something
+// other than operator-controller.
+func NewForeignCRDVariable(crdName string, owner string, bundleEntities ...*olmentity.BundleEntity) (*ForeignCRDVariable, error) {
+	id := deppy.IdentifierFromString(fmt.Sprintf("CustomResourceDefinition %s", crdName))
+	constraints := []deppy.Constraint{
+		constraint.NewUserFriendlyConstraint(constraint.Mandatory(), func(_ deppy.Constraint, subject deppy.Identifier) string {
+			return fmt.Sprintf("%s is owned by %s", subject, owner)
+		}),
+	}
+	for _, bundleEntity := range bundleEntities {
+		packageName, err := bundleEntity.PackageName()
+		if err != nil {
+			return nil, err
+		}
+		version, err := bundleEntity.Version()
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, constraint.NewUserFriendlyConstraint(constraint.Conflict(bundleEntity.ID), func(_ deppy.Constraint, subject deppy.Identifier) string {
+			return fmt.Sprintf("%s conflicts with package %s at version %s", subject, packageName, version)
+		}))
+	}
+	return &ForeignCRDVariable{
+		SimpleVariable: input.NewSimpleVariable(id, constraints...),
+	}, nil
+}
+
+var _ input.VariableSource = &ForeignCRDConstraintsVariableSource{}
+
+// ForeignCRDConstraintsVariableSource produces variables that keep bundles out of the solution if they
+// provide a gvk whose CRD already exists on the cluster and was installed by something other than
+// operator-controller, e.g. by hand, by OLM v0 or by Helm.
+// CRDs installed by rukpak for the BundleDeployment of one of the given Operators are owned by
+// operator-controller: conflicts between Operators are handled by the CRDUniquenessConstraintsVariableSource.
+type ForeignCRDConstraintsVariableSource struct {
+	inputVariableSource input.VariableSource
+	clusterCRDs         []apiextensionsv1.CustomResourceDefinition
+	operatorNames       sets.String
+}
+
+// NewForeignCRDConstraintsVariableSource creates a new instance of the ForeignCRDConstraintsVariableSource
+// from the CRDs that exist on the cluster and the names of the Operators managed by operator-controller.
+func NewForeignCRDConstraintsVariableSource(inputVariableSource input.VariableSource, clusterCRDs []apiextensionsv1.CustomResourceDefinition, operatorNames ...string) *ForeignCRDConstraintsVariableSource {
+	return &ForeignCRDConstraintsVariableSource{
+		inputVariableSource: inputVariableSource,
+		clusterCRDs:         clusterCRDs,
+		operatorNames:       sets.NewString(operatorNames...),
+	}
+}
+
+func (f *ForeignCRDConstraintsVariableSource) GetVariables(ctx context.Context, entitySource input.EntitySource) ([]deppy.Variable, error) {
+	variables, err := f.inputVariableSource.GetVariables(ctx, entitySource)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignCRDs := map[schema.GroupKind]string{}
+	owners := map[string]string{}
+	for i := range f.clusterCRDs {
+		crd := &f.clusterCRDs[i]
+		if owner := f.crdOwner(crd); owner != "" {
+			foreignCRDs[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd.GetName()
+			owners[crd.GetName()] = owner
+		}
+	}
+	if len(foreignCRDs) == 0 {
+		return variables, nil
+	}
+
+	crdToBundleMap := map[string]map[deppy.Identifier]*olmentity.BundleEntity{}
+	for _, variable := range variables {
+		switch v := variable.(type) {
+		case *bundles_and_dependencies.BundleVariable:
+			bundleEntities := []*olmentity.BundleEntity{v.BundleEntity()}
+			bundleEntities = append(bundleEntities, v.Dependencies()...)
+			for _, bundleEntity := range bundleEntities {
+				exportedGVKs, err := bundleEntity.ProvidedGVKs()
+				if err != nil {
+					return nil, fmt.Errorf("error creating foreign CRD constraints: %w", err)
+				}
+				for _, gvk := range exportedGVKs {
+					crdName, ok := foreignCRDs[schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}]
+					if !ok {
+						continue
+					}
+					if _, ok := crdToBundleMap[crdName]; !ok {
+						crdToBundleMap[crdName] = map[deppy.Identifier]*olmentity.BundleEntity{}
+					}
+					crdToBundleMap[crdName][bundleEntity.ID] = bundleEntity
+				}
+			}
+		}
+	}
+
+	crdNames := make([]string, 0, len(crdToBundleMap))
+	for crdName := range crdToBundleMap {
+		crdNames = append(crdNames, crdName)
+	}
+	sort.Strings(crdNames)
+	for _, crdName := range crdNames {
+		bundleMap := crdToBundleMap[crdName]
+		var bundleEntities []*olmentity.BundleEntity
+		for _, bundleEntity := range bundleMap {
+			bundleEntities = append(bundleEntities, bundleEntity)
+		}
+		sort.Slice(bundleEntities, func(i, j int) bool {
+			return bundleEntities[i].ID < bundleEntities[j].ID
+		})
+		variable, err := NewForeignCRDVariable(crdName, owners[crdName], bundleEntities...)
+		if err != nil {
+			return nil, fmt.Errorf("error creating foreign CRD constraints: %w", err)
+		}
+		variables = append(variables, variable)
+	}
+	return variables, nil
+}
+
+// InstalledByOperator returns true if the CRD was installed by rukpak for the BundleDeployment of one
+// of the Operators with the given names, i.e. by operator-controller.
+func InstalledByOperator(crd metav1.Object, operatorNames sets.String) bool {
+	labels := crd.GetLabels()
+	return labels[rukpakOwnerKindLabel] == "BundleDeployment" && operatorNames.Has(labels[rukpakOwnerNameLabel])
+}
+
+// crdOwner describes the owner of the given CRD, or returns an empty string if the CRD is owned
+// by operator-controller.
+func (f *ForeignCRDConstraintsVariableSource) crdOwner(crd *apiextensionsv1.CustomResourceDefinition) string {
+	if InstalledByOperator(crd, f.operatorNames) {
+		return ""
+	}
+	labels := crd.GetLabels()
+	if ownerKind, ownerName := labels[rukpakOwnerKindLabel], labels[rukpakOwnerNameLabel]; ownerKind != "" {
+		return fmt.Sprintf("%s %q", ownerKind, ownerName)
+	}
+	if controller := metav1.GetControllerOf(crd); controller != nil {
+		return fmt.Sprintf("%s %q", controller.Kind, controller.Name)
+	}
+	if managedBy := labels[managedByLabel]; managedBy != "" {
+		annotations := crd.GetAnnotations()
+		if managedBy == "Helm" && annotations[helmReleaseNameAnnotation] != "" {
+			return fmt.Sprintf("Helm release %q", strings.TrimPrefix(annotations[helmReleaseNSAnnotation]+"/"+annotations[helmReleaseNameAnnotation], "/"))
+		}
+		return managedBy
+	}
+	for label := range labels {
+		if strings.HasPrefix(label, olmOperatorLabelPrefix) {
+			return fmt.Sprintf("OLM operator %q", strings.TrimPrefix(label, olmOperatorLabelPrefix))
+		}
+	}
+	return "an unknown owner"
+}
@@ -0,0 +1,138 @@
+package crd_constraints_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/deppy/pkg/deppy"
+	"github.com/operator-framework/deppy/pkg/deppy/input"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/crd_constraints"
+	olmentity "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+var _ = Describe("ForeignCRDConstraintsVariableSource", func() {
+	var (
+		inputVariableSource *MockInputVariableSource
+		ctx                 context.Context
+		entitySource        input.EntitySource
+	)
+
+	BeforeEach(func() {
+		inputVariableSource = &MockInputVariableSource{
+			ResultSet: []deppy.Variable{
+				bundles_and_dependencies.NewBundleVariable(
+					olmentity.NewBundleEntity(bundleSet["bundle-14"]),
+					[]*olmentity.BundleEntity{olmentity.NewBundleEntity(bundleSet["bundle-9"])},
+				),
+				bundles_and_dependencies.NewBundleVariable(olmentity.NewBundleEntity(bundleSet["bundle-15"]), nil),
+			},
+		}
+		ctx = context.Background()
+
+		// the entity is not used in this variable source
+		entitySource = &PanicEntitySource{}
+	})
+
+	It("should not add variables when there is no foreign CRD", func() {
+		variableSource := crd_constraints.NewForeignCRDConstraintsVariableSource(inputVariableSource, []apiextensionsv1.CustomResourceDefinition{
+			crd("bars.bar.io", "bar.io", "Bar", withLabels(map[string]string{"core.rukpak.io/owner-kind": "BundleDeployment", "core.rukpak.io/owner-name": "bar"})),
+		}, "bar")
+		variables, err := variableSource.GetVariables(ctx, entitySource)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(variables).To(Equal(inputVariableSource.ResultSet))
+	})
+
+	It("should make the bundles providing a foreign CRD conflict with it", func() {
+		variableSource := crd_constraints.NewForeignCRDConstraintsVariableSource(inputVariableSource, []apiextensionsv1.CustomResourceDefinition{
+			crd("buzs.buz.io", "buz.io", "Buz", withLabels(map[string]string{"app.kubernetes.io/managed-by": "Helm"}),
+				withAnnotations(map[string]string{"meta.helm.sh/release-name": "buz", "meta.helm.sh/release-namespace": "default"})),
+			crd("bars.bar.io", "bar.io", "Bar", withLabels(map[string]string{"core.rukpak.io/owner-kind": "BundleDeployment", "core.rukpak.io/owner-name": "bar"})),
+			crd("fizs.fiz.io", "fiz.io", "Fiz", withLabels(map[string]string{"operators.coreos.com/fiz.operators": ""})),
+		}, "buz")
+		variables, err := variableSource.GetVariables(ctx, entitySource)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(variables).To(HaveLen(4))
+		Expect(constraintMessages(variables[2])).To(Equal([]string{
+			`CustomResourceDefinition bars.bar.io is owned by BundleDeployment "bar"`,
+			"CustomResourceDefinition bars.bar.io conflicts with package bar-package at version 1.0.0",
+		}))
+		Expect(constraintMessages(variables[3])).To(Equal([]string{
+			`CustomResourceDefinition buzs.buz.io is owned by Helm release "default/buz"`,
+			"CustomResourceDefinition buzs.buz.io conflicts with package test-package-2 at version 1.5.0",
+			"CustomResourceDefinition buzs.buz.io conflicts with package test-package-2 at version 2.0.1",
+		}))
+	})
+
+	It("should describe the owner of foreign CRDs", func() {
+		owners := map[string]apiextensionsv1.CustomResourceDefinition{
+			`OLM operator "buz.operators"`: crd("buzs.buz.io", "buz.io", "Buz", withLabels(map[string]string{"operators.coreos.com/buz.operators": ""})),
+			"ArgoCD":                       crd("buzs.buz.io", "buz.io", "Buz", withLabels(map[string]string{"app.kubernetes.io/managed-by": "ArgoCD"})),
+			`Bundle "buz"`:                 crd("buzs.buz.io", "buz.io", "Buz", withController("Bundle", "buz")),
+			"an unknown owner":             crd("buzs.buz.io", "buz.io", "Buz"),
+		}
+		for owner, foreignCRD := range owners {
+			variableSource := crd_constraints.NewForeignCRDConstraintsVariableSource(inputVariableSource, []apiextensionsv1.CustomResourceDefinition{foreignCRD})
+			variables, err := variableSource.GetVariables(ctx, entitySource)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(variables).To(HaveLen(3))
+			Expect(constraintMessages(variables[2])[0]).To(Equal(fmt.Sprintf("CustomResourceDefinition buzs.buz.io is owned by %s", owner)))
+		}
+	})
+
+	It("should return an error if input variable source returns an error", func() {
+		inputVariableSource = &MockInputVariableSource{Err: fmt.Errorf("error getting variables")}
+		variableSource := crd_constraints.NewForeignCRDConstraintsVariableSource(inputVariableSource, nil)
+		_, err := variableSource.GetVariables(ctx, entitySource)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("error getting variables"))
+	})
+})
+
+type crdOption func(*apiextensionsv1.CustomResourceDefinition)
+
+func withLabels(labels map[string]string) crdOption {
+	return func(crd *apiextensionsv1.CustomResourceDefinition) {
+		crd.SetLabels(labels)
+	}
+}
+
+func withAnnotations(annotations map[string]string) crdOption {
+	return func(crd *apiextensionsv1.CustomResourceDefinition) {
+		crd.SetAnnotations(annotations)
+	}
+}
+
+func withController(kind, name string) crdOption {
+	return func(crd *apiextensionsv1.CustomResourceDefinition) {
+		controller := true
+		crd.SetOwnerReferences([]metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}})
+	}
+}
+
+func crd(name, group, kind string, opts ...crdOption) apiextensionsv1.CustomResourceDefinition {
+	crd := apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind},
+		},
+	}
+	for _, opt := range opts {
+		opt(&crd)
+	}
+	return crd
+}
+
+func constraintMessages(variable deppy.Variable) []string {
+	var messages []string
+	for _, c := range variable.Constraints() {
+		messages = append(messages, c.String(variable.Identifier()))
+	}
+	return messages
+}
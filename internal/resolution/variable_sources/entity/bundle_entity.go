@@ -8,9 +8,44 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
 	"github.com/operator-framework/operator-registry/alpha/property"
+	"sigs.k8s.io/yaml"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
 )
 
-const PropertyBundlePath = "olm.bundle.path"
+const (
+	PropertyBundlePath  = "olm.bundle.path"
+	PropertyBundleName  = "olm.bundle.name"
+	PropertyCatalogName = "olm.catalog.name"
+	PropertyHealthCheck = "olm.healthcheck"
+	PropertyCSVMetadata = "olm.csv.metadata"
+
+	// AnnotationSuggestedNamespace is the ClusterServiceVersion annotation suggesting the namespace
+	// to install a bundle in.
+	AnnotationSuggestedNamespace = "operatorframework.io/suggested-namespace"
+)
+
+// InstallModeType is a way an operator can be installed, as declared in its ClusterServiceVersion.
+type InstallModeType string
+
+const (
+	InstallModeTypeOwnNamespace    InstallModeType = "OwnNamespace"
+	InstallModeTypeSingleNamespace InstallModeType = "SingleNamespace"
+	InstallModeTypeMultiNamespace  InstallModeType = "MultiNamespace"
+	InstallModeTypeAllNamespaces   InstallModeType = "AllNamespaces"
+)
+
+type InstallMode struct {
+	Type      InstallModeType `json:"type"`
+	Supported bool            `json:"supported"`
+}
+
+// csvMetadata is the part of the ClusterServiceVersion of a bundle carried by its olm.csv.metadata
+// property, or found in the ClusterServiceVersion among its bundle objects.
+type csvMetadata struct {
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	InstallModes []InstallMode     `json:"installModes,omitempty"`
+}
 
 type ChannelProperties struct {
 	property.Channel
@@ -58,6 +93,11 @@ type BundleEntity struct {
 	channelProperties *ChannelProperties
 	semVersion        *semver.Version
 	bundlePath        string
+	bundleName        *string
+	catalogName       *string
+	healthChecks      []operatorsv1alpha1.HealthCheck
+	bundleObjects     []property.BundleObject
+	csvMetadata       *csvMetadata
 	mu                sync.RWMutex
 }
 
@@ -124,6 +164,88 @@ func (b *BundleEntity) BundlePath() (string, error) {
 	return b.bundlePath, nil
 }
 
+// BundleName returns the name of the bundle, or an empty string if the entity source does not provide it.
+func (b *BundleEntity) BundleName() (string, error) {
+	if err := b.loadBundleName(); err != nil {
+		return "", err
+	}
+	return *b.bundleName, nil
+}
+
+// CatalogName returns the name of the catalog providing the bundle, or an empty string if the entity
+// source does not provide it.
+func (b *BundleEntity) CatalogName() (string, error) {
+	if err := b.loadCatalogName(); err != nil {
+		return "", err
+	}
+	return *b.catalogName, nil
+}
+
+// HealthChecks returns the health checks declared by the bundle.
+func (b *BundleEntity) HealthChecks() ([]operatorsv1alpha1.HealthCheck, error) {
+	if err := b.loadHealthChecks(); err != nil {
+		return nil, err
+	}
+	return b.healthChecks, nil
+}
+
+// BundleObjects returns the manifests of the objects installed by the bundle, if the entity source provides them.
+func (b *BundleEntity) BundleObjects() ([]property.BundleObject, error) {
+	if err := b.loadBundleObjects(); err != nil {
+		return nil, err
+	}
+	return b.bundleObjects, nil
+}
+
+// InstallModes returns the install modes declared by the bundle, or none if the entity source does not
+// provide the bundle's ClusterServiceVersion.
+func (b *BundleEntity) InstallModes() ([]InstallMode, error) {
+	if err := b.loadCSVMetadata(); err != nil {
+		return nil, err
+	}
+	return b.csvMetadata.InstallModes, nil
+}
+
+// SuggestedNamespace returns the namespace the bundle suggests to be installed in, or an empty string
+// if it suggests none.
+func (b *BundleEntity) SuggestedNamespace() (string, error) {
+	if err := b.loadCSVMetadata(); err != nil {
+		return "", err
+	}
+	return b.csvMetadata.Annotations[AnnotationSuggestedNamespace], nil
+}
+
+// DefaultInstallNamespace returns the namespace the bundle is installed in unless another namespace is
+// asked for: the namespace it suggests, or <packageName>-system if it suggests none.
+func (b *BundleEntity) DefaultInstallNamespace() (string, error) {
+	suggestedNamespace, err := b.SuggestedNamespace()
+	if err != nil {
+		return "", err
+	}
+	if suggestedNamespace != "" {
+		return suggestedNamespace, nil
+	}
+	packageName, err := b.PackageName()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-system", packageName), nil
+}
+
+// SupportsInstallMode returns true if the bundle declares support for the given install mode.
+func (b *BundleEntity) SupportsInstallMode(installModeType InstallModeType) (bool, error) {
+	installModes, err := b.InstallModes()
+	if err != nil {
+		return false, err
+	}
+	for _, installMode := range installModes {
+		if installMode.Type == installModeType {
+			return installMode.Supported, nil
+		}
+	}
+	return false, nil
+}
+
 func (b *BundleEntity) loadPackage() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -216,6 +338,111 @@ func (b *BundleEntity) loadBundlePath() error {
 	return nil
 }
 
+func (b *BundleEntity) loadBundleName() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.bundleName == nil {
+		bundleName, err := loadFromEntity[string](b.Entity, PropertyBundleName, optional)
+		if err != nil {
+			return fmt.Errorf("error determining bundle name for entity '%s': %w", b.ID, err)
+		}
+		b.bundleName = &bundleName
+	}
+	return nil
+}
+
+func (b *BundleEntity) loadCatalogName() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.catalogName == nil {
+		catalogName, err := loadFromEntity[string](b.Entity, PropertyCatalogName, optional)
+		if err != nil {
+			return fmt.Errorf("error determining catalog name for entity '%s': %w", b.ID, err)
+		}
+		b.catalogName = &catalogName
+	}
+	return nil
+}
+
+func (b *BundleEntity) loadHealthChecks() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.healthChecks == nil {
+		healthChecks, err := loadFromEntity[[]operatorsv1alpha1.HealthCheck](b.Entity, PropertyHealthCheck, optional)
+		if err != nil {
+			return fmt.Errorf("error determining bundle health checks for entity '%s': %w", b.ID, err)
+		}
+		b.healthChecks = healthChecks
+	}
+	return nil
+}
+
+func (b *BundleEntity) loadBundleObjects() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.bundleObjects == nil {
+		bundleObjects, err := loadFromEntity[[]property.BundleObject](b.Entity, property.TypeBundleObject, optional)
+		if err != nil {
+			return fmt.Errorf("error determining bundle objects for entity '%s': %w", b.ID, err)
+		}
+		b.bundleObjects = bundleObjects
+	}
+	return nil
+}
+
+func (b *BundleEntity) loadCSVMetadata() error {
+	// the bundle objects are only needed without the csv metadata property, and load under the same lock
+	if _, ok := b.Entity.Properties[PropertyCSVMetadata]; !ok {
+		if err := b.loadBundleObjects(); err != nil {
+			return err
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.csvMetadata == nil {
+		metadata, err := loadFromEntity[csvMetadata](b.Entity, PropertyCSVMetadata, optional)
+		if err != nil {
+			return fmt.Errorf("error determining csv metadata for entity '%s': %w", b.ID, err)
+		}
+		if _, ok := b.Entity.Properties[PropertyCSVMetadata]; !ok {
+			if metadata, err = csvMetadataFromBundleObjects(b.bundleObjects); err != nil {
+				return fmt.Errorf("error determining csv metadata for entity '%s': %w", b.ID, err)
+			}
+		}
+		b.csvMetadata = &metadata
+	}
+	return nil
+}
+
+// csvMetadataFromBundleObjects reads the metadata of the ClusterServiceVersion found among the
+// given bundle objects, if any.
+func csvMetadataFromBundleObjects(bundleObjects []property.BundleObject) (csvMetadata, error) {
+	for _, bundleObject := range bundleObjects {
+		if bundleObject.IsRef() {
+			continue
+		}
+		data, err := bundleObject.GetData(nil, "")
+		if err != nil {
+			return csvMetadata{}, err
+		}
+		csv := struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Annotations map[string]string `json:"annotations,omitempty"`
+			} `json:"metadata"`
+			Spec csvMetadata `json:"spec"`
+		}{}
+		if err := yaml.Unmarshal(data, &csv); err != nil {
+			return csvMetadata{}, err
+		}
+		if csv.Kind == "ClusterServiceVersion" {
+			csv.Spec.Annotations = csv.Metadata.Annotations
+			return csv.Spec, nil
+		}
+	}
+	return csvMetadata{}, nil
+}
+
 func loadFromEntity[T interface{}](entity *input.Entity, propertyName string, required propertyRequirement) (T, error) {
 	deserializedProperty := *new(T)
 	propertyValue, ok := entity.Properties[propertyName]
@@ -1,6 +1,8 @@
 package entity_test
 
 import (
+	"encoding/base64"
+	"fmt"
 	"testing"
 
 	"github.com/blang/semver/v4"
@@ -9,6 +11,7 @@ import (
 	"github.com/operator-framework/deppy/pkg/deppy/input"
 	"github.com/operator-framework/operator-registry/alpha/property"
 
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
 	olmentity "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
 )
 
@@ -267,4 +270,208 @@ var _ = Describe("BundleEntity", func() {
 			Expect(err.Error()).To(Equal("error determining bundle path for entity 'operatorhub/prometheus/0.14.0': property 'olm.bundle.path' ('badBundlePath') could not be parsed: invalid character 'b' looking for beginning of value"))
 		})
 	})
+
+	Describe("BundleName", func() {
+		It("should return the bundle name if present", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.bundle.name": `"prometheusoperator.0.14.0"`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleName, err := bundleEntity.BundleName()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundleName).To(Equal("prometheusoperator.0.14.0"))
+		})
+		It("should return an empty name if the property is not found", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleName, err := bundleEntity.BundleName()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundleName).To(BeEmpty())
+		})
+		It("should return error if the property is malformed", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.bundle.name": "badBundleName",
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleName, err := bundleEntity.BundleName()
+			Expect(bundleName).To(BeEmpty())
+			Expect(err.Error()).To(Equal("error determining bundle name for entity 'operatorhub/prometheus/0.14.0': property 'olm.bundle.name' ('badBundleName') could not be parsed: invalid character 'b' looking for beginning of value"))
+		})
+	})
+
+	Describe("CatalogName", func() {
+		It("should return the catalog name if present", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.catalog.name": `"operatorhub"`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			catalogName, err := bundleEntity.CatalogName()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(catalogName).To(Equal("operatorhub"))
+		})
+		It("should return an empty name if the property is not found", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			catalogName, err := bundleEntity.CatalogName()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(catalogName).To(BeEmpty())
+		})
+	})
+
+	Describe("HealthChecks", func() {
+		It("should return the bundle health checks if present", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.healthcheck": `[{"name":"healthz","httpGet":{"url":"http://prometheus-operator.default.svc:8080/healthz"}}]`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			healthChecks, err := bundleEntity.HealthChecks()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(healthChecks).To(Equal([]operatorsv1alpha1.HealthCheck{{
+				Name:    "healthz",
+				HTTPGet: &operatorsv1alpha1.HTTPGetHealthCheck{URL: "http://prometheus-operator.default.svc:8080/healthz"},
+			}}))
+		})
+		It("should return no health checks if the property is not found", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			healthChecks, err := bundleEntity.HealthChecks()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(healthChecks).To(BeEmpty())
+		})
+		It("should return error if the property is malformed", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.healthcheck": "badHealthChecks",
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			healthChecks, err := bundleEntity.HealthChecks()
+			Expect(healthChecks).To(BeNil())
+			Expect(err.Error()).To(Equal("error determining bundle health checks for entity 'operatorhub/prometheus/0.14.0': property 'olm.healthcheck' ('badHealthChecks') could not be parsed: invalid character 'b' looking for beginning of value"))
+		})
+	})
+	Describe("BundleObjects", func() {
+		It("should return the bundle objects if present", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.bundle.object": `[{"data":"eyJraW5kIjoiQ29uZmlnTWFwIn0="}]`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleObjects, err := bundleEntity.BundleObjects()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundleObjects).To(HaveLen(1))
+			data, err := bundleObjects[0].GetData(nil, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"kind":"ConfigMap"}`))
+		})
+		It("should return no bundle objects if the property is not found", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleObjects, err := bundleEntity.BundleObjects()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundleObjects).To(BeEmpty())
+		})
+		It("should return error if the property is malformed", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.bundle.object": "badBundleObjects",
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleObjects, err := bundleEntity.BundleObjects()
+			Expect(bundleObjects).To(BeNil())
+			Expect(err.Error()).To(Equal("error determining bundle objects for entity 'operatorhub/prometheus/0.14.0': property 'olm.bundle.object' ('badBundleObjects') could not be parsed: invalid character 'b' looking for beginning of value"))
+		})
+	})
+	Describe("InstallModes", func() {
+		It("should return the install modes from the csv metadata", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.csv.metadata": `{"installModes":[{"type":"AllNamespaces","supported":true}]}`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			installModes, err := bundleEntity.InstallModes()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(installModes).To(Equal([]olmentity.InstallMode{{Type: olmentity.InstallModeTypeAllNamespaces, Supported: true}}))
+			supported, err := bundleEntity.SupportsInstallMode(olmentity.InstallModeTypeAllNamespaces)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(supported).To(BeTrue())
+		})
+		It("should return the install modes from the csv among the bundle objects", func() {
+			csv := base64.StdEncoding.EncodeToString([]byte(`{"kind":"ClusterServiceVersion","spec":{"installModes":[{"type":"OwnNamespace","supported":true}]}}`))
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.bundle.object": fmt.Sprintf(`[{"data":"eyJraW5kIjoiQ29uZmlnTWFwIn0="},{"data":%q}]`, csv),
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			installModes, err := bundleEntity.InstallModes()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(installModes).To(Equal([]olmentity.InstallMode{{Type: olmentity.InstallModeTypeOwnNamespace, Supported: true}}))
+			supported, err := bundleEntity.SupportsInstallMode(olmentity.InstallModeTypeAllNamespaces)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(supported).To(BeFalse())
+		})
+		It("should return no install modes if the bundle does not declare them", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			installModes, err := bundleEntity.InstallModes()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(installModes).To(BeEmpty())
+		})
+		It("should return error if the property is malformed", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.csv.metadata": "badCSVMetadata",
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			installModes, err := bundleEntity.InstallModes()
+			Expect(installModes).To(BeNil())
+			Expect(err.Error()).To(Equal("error determining csv metadata for entity 'operatorhub/prometheus/0.14.0': property 'olm.csv.metadata' ('badCSVMetadata') could not be parsed: invalid character 'b' looking for beginning of value"))
+		})
+	})
+
+	Describe("DefaultInstallNamespace", func() {
+		It("should return the suggested namespace", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.package":      `{"packageName":"prometheus","version":"0.14.0"}`,
+				"olm.csv.metadata": `{"annotations":{"operatorframework.io/suggested-namespace":"monitoring"}}`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			defaultNamespace, err := bundleEntity.DefaultInstallNamespace()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defaultNamespace).To(Equal("monitoring"))
+		})
+		It("should return a namespace named after the package if the bundle suggests none", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.package": `{"packageName":"prometheus","version":"0.14.0"}`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			defaultNamespace, err := bundleEntity.DefaultInstallNamespace()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defaultNamespace).To(Equal("prometheus-system"))
+		})
+	})
+
+	Describe("SuggestedNamespace", func() {
+		It("should return the suggested namespace from the csv metadata", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.csv.metadata": `{"annotations":{"operatorframework.io/suggested-namespace":"monitoring"}}`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			suggestedNamespace, err := bundleEntity.SuggestedNamespace()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(suggestedNamespace).To(Equal("monitoring"))
+		})
+		It("should return the suggested namespace from the csv among the bundle objects", func() {
+			csv := base64.StdEncoding.EncodeToString([]byte(`{"kind":"ClusterServiceVersion","metadata":{"annotations":{"operatorframework.io/suggested-namespace":"monitoring"}}}`))
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.bundle.object": fmt.Sprintf(`[{"data":%q}]`, csv),
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			suggestedNamespace, err := bundleEntity.SuggestedNamespace()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(suggestedNamespace).To(Equal("monitoring"))
+		})
+		It("should return an empty string if the bundle suggests no namespace", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				"olm.csv.metadata": `{"installModes":[{"type":"AllNamespaces","supported":true}]}`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			suggestedNamespace, err := bundleEntity.SuggestedNamespace()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(suggestedNamespace).To(BeEmpty())
+		})
+	})
 })
@@ -15,12 +15,20 @@ import (
 var _ input.VariableSource = &OLMVariableSource{}
 
 type OLMVariableSource struct {
-	operators []operatorsv1alpha1.Operator
+	operators  []operatorsv1alpha1.Operator
+	servedGVKs bundles_and_dependencies.ServedGVKs
 }
 
 func NewOLMVariableSource(operators ...operatorsv1alpha1.Operator) *OLMVariableSource {
+	return NewOLMVariableSourceWithServedGVKs(nil, operators...)
+}
+
+// NewOLMVariableSourceWithServedGVKs creates a new OLMVariableSource that satisfies the gvks required by
+// bundles with the gvks served by the cluster, before looking for bundles providing them.
+func NewOLMVariableSourceWithServedGVKs(servedGVKs bundles_and_dependencies.ServedGVKs, operators ...operatorsv1alpha1.Operator) *OLMVariableSource {
 	return &OLMVariableSource{
-		operators: operators,
+		operators:  operators,
+		servedGVKs: servedGVKs,
 	}
 }
 
@@ -37,7 +45,7 @@ func (o *OLMVariableSource) GetVariables(ctx context.Context, entitySource input
 	}
 
 	// build variable source pipeline
-	variableSource := crd_constraints.NewCRDUniquenessConstraintsVariableSource(bundles_and_dependencies.NewBundlesAndDepsVariableSource(inputVariableSources...))
+	variableSource := crd_constraints.NewCRDUniquenessConstraintsVariableSource(bundles_and_dependencies.NewBundlesAndDepsVariableSourceWithServedGVKs(o.servedGVKs, inputVariableSources...))
 	return variableSource.GetVariables(ctx, entitySource)
 }
 
@@ -49,5 +57,17 @@ func (o *OLMVariableSource) requiredPackageFromOperator(operator *operatorsv1alp
 	if operator.Spec.Channel != "" {
 		opts = append(opts, required_package.InChannel(operator.Spec.Channel))
 	}
+	// stay on the bundle the Operator was rolled back to, until its spec changes or the catalog
+	// provides a bundle that may fix the failed one: falling back to the next-highest version
+	// instead could fail too and never settle
+	if failed := operator.Status.FailedBundle; operator.Spec.RollbackOnFailure && failed != nil && failed.Generation == operator.GetGeneration() {
+		if lastKnownGood := operator.Status.LastKnownGoodBundle; lastKnownGood != nil {
+			opts = append(opts, required_package.PinnedToBundleUntilFixed(lastKnownGood.Image, failed.Image, failed.Version))
+		} else {
+			opts = append(opts, required_package.ExcludingBundles(failed.Image))
+		}
+	}
+	// only bundles that can watch the namespaces the Operator asks for can be installed
+	opts = append(opts, required_package.WatchingNamespaces(operator.Spec.InstallNamespace, operator.Spec.WatchNamespaces...))
 	return required_package.NewRequiredPackage(operator.Spec.PackageName, opts...)
 }
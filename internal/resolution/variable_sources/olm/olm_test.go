@@ -59,6 +59,17 @@ func withVersionRange(versionRange string) opOption {
 	}
 }
 
+func withRollbackFrom(bundlePath string) opOption {
+	return func(op *operatorsv1alpha1.Operator) error {
+		op.Spec.RollbackOnFailure = true
+		op.Status.FailedBundle = &operatorsv1alpha1.FailedBundle{
+			BundleMetadata: operatorsv1alpha1.BundleMetadata{Image: bundlePath},
+			Generation:     op.GetGeneration(),
+		}
+		return nil
+	}
+}
+
 func operator(name string, opts ...opOption) operatorsv1alpha1.Operator {
 	op := operatorsv1alpha1.Operator{
 		ObjectMeta: metav1.ObjectMeta{
@@ -137,13 +148,38 @@ var _ = Describe("OLMVariableSource", func() {
 		})))
 	})
 
+	It("should avoid the bundle an Operator was rolled back from", func() {
+		failedBundleOperator := operator("prometheus", withRollbackFrom("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+		olmVariableSource := olm.NewOLMVariableSource(failedBundleOperator)
+		variables, err := olmVariableSource.GetVariables(context.Background(), testEntitySource)
+		Expect(err).ToNot(HaveOccurred())
+
+		bundleVariables := filterVariables[*bundles_and_dependencies.BundleVariable](variables)
+		Expect(bundleVariables).To(WithTransform(func(bvars []*bundles_and_dependencies.BundleVariable) []*input.Entity {
+			var out []*input.Entity
+			for _, variable := range bvars {
+				out = append(out, variable.BundleEntity().Entity)
+			}
+			return out
+		}, Equal([]*input.Entity{
+			entityFromCache("operatorhub/prometheus/0.37.0"),
+		})))
+
+		By("no longer avoiding the bundle once the spec changes")
+		failedBundleOperator.Generation++
+		olmVariableSource = olm.NewOLMVariableSource(failedBundleOperator)
+		variables, err = olmVariableSource.GetVariables(context.Background(), testEntitySource)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filterVariables[*bundles_and_dependencies.BundleVariable](variables)).To(HaveLen(2))
+	})
+
 	It("should produce GlobalConstraints variables", func() {
 		olmVariableSource := olm.NewOLMVariableSource(operator("prometheus"), operator("packageA"))
 		variables, err := olmVariableSource.GetVariables(context.Background(), testEntitySource)
 		Expect(err).ToNot(HaveOccurred())
 
 		globalConstraintsVariables := filterVariables[*crd_constraints.BundleUniquenessVariable](variables)
-		Expect(globalConstraintsVariables).To(HaveLen(6))
+		Expect(globalConstraintsVariables).To(HaveLen(5))
 
 		// check global variables have the right names
 		Expect(globalConstraintsVariables).To(WithTransform(func(gvars []*crd_constraints.BundleUniquenessVariable) []string {
@@ -156,10 +192,9 @@ var _ = Describe("OLMVariableSource", func() {
 			})
 			return out
 		}, Equal([]string{
-			"group:\"foo.io\" version:\"v1\" kind:\"Foo\" gvk uniqueness",
-			"group:\"monitoring.coreos.com\" version:\"v1\" kind:\"Alertmanager\" gvk uniqueness",
-			"group:\"monitoring.coreos.com\" version:\"v1\" kind:\"Prometheus\" gvk uniqueness",
-			"group:\"monitoring.coreos.com\" version:\"v1alpha1\" kind:\"Prometheus\" gvk uniqueness",
+			"Alertmanager.monitoring.coreos.com crd uniqueness",
+			"Foo.foo.io crd uniqueness",
+			"Prometheus.monitoring.coreos.com crd uniqueness",
 			"packageA package uniqueness",
 			"prometheus package uniqueness",
 		})))
@@ -3,6 +3,7 @@ package required_package
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/blang/semver/v4"
 	"github.com/operator-framework/deppy/pkg/deppy"
@@ -64,11 +65,81 @@ func InChannel(channelName string) RequiredPackageOption {
 	}
 }
 
+func ExcludingBundles(bundlePaths ...string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		for _, bundlePath := range bundlePaths {
+			r.excludedBundles = append(r.excludedBundles, bundlePath)
+			r.predicates = append(r.predicates, input.Not(predicates.WithBundlePath(bundlePath)))
+		}
+		return nil
+	}
+}
+
+// PinnedToBundle restricts the required package to the bundle with the given path.
+func PinnedToBundle(bundlePath string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		if bundlePath != "" {
+			r.pinnedBundle = bundlePath
+			r.predicates = append(r.predicates, predicates.WithBundlePath(bundlePath))
+		}
+		return nil
+	}
+}
+
+// PinnedToBundleUntilFixed restricts the required package to the bundle with the given path, or to
+// the bundles other than the failed one whose version is not lower than the failed version, which
+// the catalog may provide to fix the failure. It pins to the bundle alone if the failed version is
+// not a valid semver version.
+func PinnedToBundleUntilFixed(bundlePath, failedBundlePath, failedVersion string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		fixedRange, err := semver.ParseRange(">=" + failedVersion)
+		if bundlePath == "" || err != nil {
+			return PinnedToBundle(bundlePath)(r)
+		}
+		r.pinnedBundle = bundlePath
+		r.predicates = append(r.predicates, input.Or(
+			predicates.WithBundlePath(bundlePath),
+			input.And(input.Not(predicates.WithBundlePath(failedBundlePath)), predicates.InSemverRange(fixedRange)),
+		))
+		return nil
+	}
+}
+
+// SupportingInstallMode restricts the required package to the bundles that can be installed in the
+// given install mode.
+func SupportingInstallMode(installModeType olmentity.InstallModeType) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		r.installable = predicates.SupportsInstallMode(installModeType)
+		r.installableDescription = fmt.Sprintf("the %s install mode", installModeType)
+		return nil
+	}
+}
+
+// WatchingNamespaces restricts the required package to the bundles whose install modes let them watch
+// the given namespaces when installed in the given namespace, or in their default install namespace if
+// none is given. Watching no namespace in particular means watching all namespaces.
+func WatchingNamespaces(installNamespace string, watchNamespaces ...string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		if len(watchNamespaces) == 0 {
+			return SupportingInstallMode(olmentity.InstallModeTypeAllNamespaces)(r)
+		}
+		r.installable = predicates.SupportsWatchNamespaces(installNamespace, watchNamespaces...)
+		r.installableDescription = fmt.Sprintf("watching namespaces %s", strings.Join(watchNamespaces, ", "))
+		return nil
+	}
+}
+
 type RequiredPackageVariableSource struct {
-	packageName  string
-	versionRange string
-	channelName  string
-	predicates   []input.Predicate
+	packageName     string
+	versionRange    string
+	channelName     string
+	excludedBundles []string
+	pinnedBundle    string
+	predicates      []input.Predicate
+
+	// installable selects the bundles that can be installed the way the package is required to be
+	installable            input.Predicate
+	installableDescription string
 }
 
 func NewRequiredPackage(packageName string, options ...RequiredPackageOption) (*RequiredPackageVariableSource, error) {
@@ -95,6 +166,20 @@ func (r *RequiredPackageVariableSource) GetVariables(ctx context.Context, entity
 	if len(resultSet) == 0 {
 		return nil, r.notFoundError()
 	}
+	// bundles that exist but can not be installed are filtered out separately, to tell them apart
+	// in the error
+	if r.installable != nil {
+		installable := input.EntityList{}
+		for i := range resultSet {
+			if r.installable(&resultSet[i]) {
+				installable = append(installable, resultSet[i])
+			}
+		}
+		resultSet = installable
+		if len(resultSet) == 0 {
+			return nil, fmt.Errorf("%s exists, but does not support %s", r.description(), r.installableDescription)
+		}
+	}
 	resultSet = resultSet.Sort(sort.ByChannelAndVersion)
 	var bundleEntities []*olmentity.BundleEntity
 	for i := 0; i < len(resultSet); i++ {
@@ -106,18 +191,34 @@ func (r *RequiredPackageVariableSource) GetVariables(ctx context.Context, entity
 }
 
 func (r *RequiredPackageVariableSource) notFoundError() error {
+	err := r.constraintsNotFoundError()
+	if r.pinnedBundle != "" {
+		return fmt.Errorf("%v, pinned to bundle %s", err, r.pinnedBundle)
+	}
+	if len(r.excludedBundles) > 0 {
+		return fmt.Errorf("%v, excluding bundles that failed to install: %s", err, strings.Join(r.excludedBundles, ", "))
+	}
+	return err
+}
+
+func (r *RequiredPackageVariableSource) constraintsNotFoundError() error {
+	return fmt.Errorf("%s not found", r.description())
+}
+
+// description describes the required package and the constraints on its version.
+func (r *RequiredPackageVariableSource) description() string {
 	// TODO: update this error message when/if we decide to support version ranges as opposed to fixing the version
 	//  context: we originally wanted to support version ranges and take the highest version that satisfies the range
 	//  during the upstream call on the 2023-04-11 we decided to pin the version instead. But, we'll keep version range
 	//  support under the covers in case we decide to pivot back.
 	if r.versionRange != "" && r.channelName != "" {
-		return fmt.Errorf("package '%s' at version '%s' in channel '%s' not found", r.packageName, r.versionRange, r.channelName)
+		return fmt.Sprintf("package '%s' at version '%s' in channel '%s'", r.packageName, r.versionRange, r.channelName)
 	}
 	if r.versionRange != "" {
-		return fmt.Errorf("package '%s' at version '%s' not found", r.packageName, r.versionRange)
+		return fmt.Sprintf("package '%s' at version '%s'", r.packageName, r.versionRange)
 	}
 	if r.channelName != "" {
-		return fmt.Errorf("package '%s' in channel '%s' not found", r.packageName, r.channelName)
+		return fmt.Sprintf("package '%s' in channel '%s'", r.packageName, r.channelName)
 	}
-	return fmt.Errorf("package '%s' not found", r.packageName)
+	return fmt.Sprintf("package '%s'", r.packageName)
 }
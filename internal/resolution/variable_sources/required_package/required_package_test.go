@@ -144,6 +144,193 @@ var _ = Describe("RequiredPackageVariableSource", func() {
 		}))
 	})
 
+	It("should filter out excluded bundles", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-1": *input.NewEntity("bundle-1", map[string]string{
+				property.TypePackage:         `{"packageName": "test-package", "version": "1.0.0"}`,
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: `"registry.io/test-package:v1.0.0"`,
+			}),
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage:         `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: `"registry.io/test-package:v2.0.0"`,
+			}),
+		})
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.ExcludingBundles("registry.io/test-package:v2.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(variables)).To(Equal(1))
+		reqPackageVar, ok := variables[0].(*required_package.RequiredPackageVariable)
+		Expect(ok).To(BeTrue())
+		Expect(reqPackageVar.BundleEntities()).To(HaveLen(1))
+		Expect(reqPackageVar.BundleEntities()[0].ID).To(Equal(deppy.IdentifierFromString("bundle-1")))
+	})
+
+	It("should mention excluded bundles if package not found", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage:         `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: `"registry.io/test-package:v2.0.0"`,
+			}),
+		})
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.ExcludingBundles("registry.io/test-package:v2.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("package 'test-package' not found, excluding bundles that failed to install: registry.io/test-package:v2.0.0"))
+	})
+
+	It("should only select the bundle it is pinned to", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-1": *input.NewEntity("bundle-1", map[string]string{
+				property.TypePackage:         `{"packageName": "test-package", "version": "1.0.0"}`,
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: `"registry.io/test-package:v1.0.0"`,
+			}),
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage:         `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: `"registry.io/test-package:v2.0.0"`,
+			}),
+			"bundle-3": *input.NewEntity("bundle-3", map[string]string{
+				property.TypePackage:         `{"packageName": "test-package", "version": "3.0.0"}`,
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: `"registry.io/test-package:v3.0.0"`,
+			}),
+		})
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.PinnedToBundle("registry.io/test-package:v1.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(variables)).To(Equal(1))
+		reqPackageVar, ok := variables[0].(*required_package.RequiredPackageVariable)
+		Expect(ok).To(BeTrue())
+		Expect(reqPackageVar.BundleEntities()).To(HaveLen(1))
+		Expect(reqPackageVar.BundleEntities()[0].ID).To(Equal(deppy.IdentifierFromString("bundle-1")))
+	})
+
+	It("should select the bundle it is pinned to, and the bundles that may fix the failed one", func() {
+		entities := map[deppy.Identifier]input.Entity{}
+		for _, version := range []string{"1.0.0", "1.5.0", "2.0.0"} {
+			id := deppy.IdentifierFromString("bundle-" + version)
+			entities[id] = *input.NewEntity(id, map[string]string{
+				property.TypePackage:         fmt.Sprintf(`{"packageName": "test-package", "version": %q}`, version),
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: fmt.Sprintf(`"registry.io/test-package:v%s"`, version),
+			})
+		}
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.PinnedToBundleUntilFixed("registry.io/test-package:v1.0.0", "registry.io/test-package:v2.0.0", "2.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), input.NewCacheQuerier(entities))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(variables[0].(*required_package.RequiredPackageVariable).BundleEntities()).To(WithTransform(bundleIDs, Equal([]deppy.Identifier{"bundle-1.0.0"})))
+
+		By("selecting a newer bundle once the catalog provides it")
+		entities["bundle-2.0.1"] = *input.NewEntity("bundle-2.0.1", map[string]string{
+			property.TypePackage:         `{"packageName": "test-package", "version": "2.0.1"}`,
+			property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+			olmentity.PropertyBundlePath: `"registry.io/test-package:v2.0.1"`,
+		})
+		variables, err = rpvs.GetVariables(context.TODO(), input.NewCacheQuerier(entities))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(variables[0].(*required_package.RequiredPackageVariable).BundleEntities()).To(WithTransform(bundleIDs, Equal([]deppy.Identifier{"bundle-2.0.1", "bundle-1.0.0"})))
+	})
+
+	It("should mention the pinned bundle if package not found", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage:         `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel:         `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyBundlePath: `"registry.io/test-package:v2.0.0"`,
+			}),
+		})
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.PinnedToBundle("registry.io/test-package:v1.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("package 'test-package' not found, pinned to bundle registry.io/test-package:v1.0.0"))
+	})
+
+	It("should filter out bundles not supporting the install mode", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-1": *input.NewEntity("bundle-1", map[string]string{
+				property.TypePackage:          `{"packageName": "test-package", "version": "1.0.0"}`,
+				property.TypeChannel:          `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"AllNamespaces","supported":true}]}`,
+			}),
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage:          `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel:          `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"AllNamespaces","supported":false},{"type":"OwnNamespace","supported":true}]}`,
+			}),
+		})
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.SupportingInstallMode(olmentity.InstallModeTypeAllNamespaces))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(variables)).To(Equal(1))
+		reqPackageVar, ok := variables[0].(*required_package.RequiredPackageVariable)
+		Expect(ok).To(BeTrue())
+		Expect(reqPackageVar.BundleEntities()).To(HaveLen(1))
+		Expect(reqPackageVar.BundleEntities()[0].ID).To(Equal(deppy.IdentifierFromString("bundle-1")))
+	})
+
+	It("should say when the package exists but does not support the install mode", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage:          `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel:          `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"OwnNamespace","supported":true}]}`,
+			}),
+		})
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.InVersionRange("2.0.0"), required_package.SupportingInstallMode(olmentity.InstallModeTypeAllNamespaces))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("package 'test-package' at version '2.0.0' exists, but does not support the AllNamespaces install mode"))
+	})
+
+	It("should filter out bundles that can not watch the namespaces", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-1": *input.NewEntity("bundle-1", map[string]string{
+				property.TypePackage:          `{"packageName": "test-package", "version": "1.0.0"}`,
+				property.TypeChannel:          `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"AllNamespaces","supported":true}]}`,
+			}),
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage:          `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel:          `{"channelName":"stable","priority":0}`,
+				olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"AllNamespaces","supported":false},{"type":"OwnNamespace","supported":true}]}`,
+			}),
+		})
+		rpvs, err := required_package.NewRequiredPackage(packageName, required_package.WatchingNamespaces("", "test-package-system"))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(variables)).To(Equal(1))
+		reqPackageVar, ok := variables[0].(*required_package.RequiredPackageVariable)
+		Expect(ok).To(BeTrue())
+		Expect(reqPackageVar.BundleEntities()).To(HaveLen(1))
+		Expect(reqPackageVar.BundleEntities()[0].ID).To(Equal(deppy.IdentifierFromString("bundle-2")))
+
+		rpvs, err = required_package.NewRequiredPackage(packageName, required_package.WatchingNamespaces("monitoring", "test-package-system"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("package 'test-package' exists, but does not support watching namespaces test-package-system"))
+	})
+
 	It("should fail with bad semver range", func() {
 		_, err := required_package.NewRequiredPackage(packageName, required_package.InVersionRange("not a valid semver"))
 		Expect(err).To(HaveOccurred())
@@ -156,3 +343,11 @@ var _ = Describe("RequiredPackageVariableSource", func() {
 		Expect(err.Error()).To(Equal("package 'test-package' not found"))
 	})
 })
+
+func bundleIDs(bundles []*olmentity.BundleEntity) []deppy.Identifier {
+	ids := make([]deppy.Identifier, 0, len(bundles))
+	for _, bundle := range bundles {
+		ids = append(ids, bundle.ID)
+	}
+	return ids
+}
@@ -40,6 +40,62 @@ func InChannel(channelName string) input.Predicate {
 	}
 }
 
+func WithBundlePath(bundlePath string) input.Predicate {
+	return func(entity *input.Entity) bool {
+		bundleEntity := olmentity.NewBundleEntity(entity)
+		path, err := bundleEntity.BundlePath()
+		if err != nil {
+			return false
+		}
+		return path == bundlePath
+	}
+}
+
+// SupportsInstallMode selects the bundles that support the given install mode. Bundles that do not
+// declare their install modes are selected too, as their support is unknown.
+func SupportsInstallMode(installModeType olmentity.InstallModeType) input.Predicate {
+	return func(entity *input.Entity) bool {
+		bundleEntity := olmentity.NewBundleEntity(entity)
+		installModes, err := bundleEntity.InstallModes()
+		if err != nil {
+			return false
+		}
+		if len(installModes) == 0 {
+			return true
+		}
+		supported, _ := bundleEntity.SupportsInstallMode(installModeType)
+		return supported
+	}
+}
+
+// SupportsWatchNamespaces selects the bundles whose install modes let them watch the given namespaces
+// when installed in the given namespace, or in their default install namespace if none is given.
+// Watching no namespace in particular means watching all namespaces.
+func SupportsWatchNamespaces(installNamespace string, watchNamespaces ...string) input.Predicate {
+	if len(watchNamespaces) == 0 {
+		return SupportsInstallMode(olmentity.InstallModeTypeAllNamespaces)
+	}
+	if len(watchNamespaces) > 1 {
+		return SupportsInstallMode(olmentity.InstallModeTypeMultiNamespace)
+	}
+	supportsSingleNamespace := SupportsInstallMode(olmentity.InstallModeTypeSingleNamespace)
+	supportsOwnNamespace := SupportsInstallMode(olmentity.InstallModeTypeOwnNamespace)
+	return func(entity *input.Entity) bool {
+		if supportsSingleNamespace(entity) {
+			return true
+		}
+		namespace := installNamespace
+		if namespace == "" {
+			defaultNamespace, err := olmentity.NewBundleEntity(entity).DefaultInstallNamespace()
+			if err != nil {
+				return false
+			}
+			namespace = defaultNamespace
+		}
+		return namespace == watchNamespaces[0] && supportsOwnNamespace(entity)
+	}
+}
+
 func ProvidesGVK(gvk *olmentity.GVK) input.Predicate {
 	return func(entity *input.Entity) bool {
 		bundleEntity := olmentity.NewBundleEntity(entity)
@@ -51,6 +51,16 @@ var _ = Describe("Predicates", func() {
 		})
 	})
 
+	Describe("WithBundlePath", func() {
+		It("should return true when the entity has the specified bundle path", func() {
+			entity := input.NewEntity("test", map[string]string{
+				olmentity.PropertyBundlePath: `"foo.io/bar/baz:v1.0.0"`,
+			})
+			Expect(predicates.WithBundlePath("foo.io/bar/baz:v1.0.0")(entity)).To(BeTrue())
+			Expect(predicates.WithBundlePath("foo.io/bar/baz:v2.0.0")(entity)).To(BeFalse())
+		})
+	})
+
 	Describe("ProvidesGVK", func() {
 		It("should return true when the entity provides the specified gvk", func() {
 			entity := input.NewEntity("test", map[string]string{
@@ -68,4 +78,41 @@ var _ = Describe("Predicates", func() {
 			})(entity)).To(BeFalse())
 		})
 	})
+	Describe("SupportsInstallMode", func() {
+		It("should return true when the entity supports the install mode", func() {
+			entity := input.NewEntity("test", map[string]string{
+				olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"AllNamespaces","supported":true},{"type":"OwnNamespace","supported":false}]}`,
+			})
+			Expect(predicates.SupportsInstallMode(olmentity.InstallModeTypeAllNamespaces)(entity)).To(BeTrue())
+			Expect(predicates.SupportsInstallMode(olmentity.InstallModeTypeOwnNamespace)(entity)).To(BeFalse())
+			Expect(predicates.SupportsInstallMode(olmentity.InstallModeTypeSingleNamespace)(entity)).To(BeFalse())
+		})
+		It("should return true when the entity does not declare its install modes", func() {
+			entity := input.NewEntity("test", map[string]string{})
+			Expect(predicates.SupportsInstallMode(olmentity.InstallModeTypeAllNamespaces)(entity)).To(BeTrue())
+		})
+	})
+	Describe("SupportsWatchNamespaces", func() {
+		ownNamespace := input.NewEntity("test", map[string]string{
+			"olm.package":                 `{"packageName":"prometheus","version":"1.0.0"}`,
+			olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"OwnNamespace","supported":true}]}`,
+		})
+		singleNamespace := input.NewEntity("test", map[string]string{
+			olmentity.PropertyCSVMetadata: `{"installModes":[{"type":"OwnNamespace","supported":true},{"type":"SingleNamespace","supported":true}]}`,
+		})
+		It("should require the AllNamespaces install mode when no namespace is watched", func() {
+			Expect(predicates.SupportsWatchNamespaces("")(ownNamespace)).To(BeFalse())
+		})
+		It("should require the OwnNamespace install mode to watch the install namespace", func() {
+			Expect(predicates.SupportsWatchNamespaces("", "prometheus-system")(ownNamespace)).To(BeTrue())
+			Expect(predicates.SupportsWatchNamespaces("monitoring", "monitoring")(ownNamespace)).To(BeTrue())
+			Expect(predicates.SupportsWatchNamespaces("monitoring", "prometheus-system")(ownNamespace)).To(BeFalse())
+		})
+		It("should require the SingleNamespace install mode to watch another namespace", func() {
+			Expect(predicates.SupportsWatchNamespaces("monitoring", "default")(singleNamespace)).To(BeTrue())
+		})
+		It("should require the MultiNamespace install mode to watch several namespaces", func() {
+			Expect(predicates.SupportsWatchNamespaces("monitoring", "monitoring", "default")(singleNamespace)).To(BeFalse())
+		})
+	})
 })